@@ -5,6 +5,10 @@ type DependenciesCache struct {
 	successes        int
 	failures         int
 	total            int
+	records          []PublishRecord
+	// The caches of the deployment targets, when publishing to multiple deployers.
+	targets     map[string]*DependenciesCache
+	targetNames []string
 }
 
 func (dc *DependenciesCache) GetMap() map[string]bool {
@@ -41,3 +45,23 @@ func (dc *DependenciesCache) initMap() {
 		dc.modulesPublished = make(map[string]bool)
 	}
 }
+
+// Returns the cache of a deployment target, and creates it if needed.
+// Each target tracks its own published modules and results, so that publishing to one target doesn't affect the others.
+func (dc *DependenciesCache) GetTarget(name string) *DependenciesCache {
+	if dc.targets == nil {
+		dc.targets = make(map[string]*DependenciesCache)
+	}
+	target, ok := dc.targets[name]
+	if !ok {
+		target = &DependenciesCache{}
+		dc.targets[name] = target
+		dc.targetNames = append(dc.targetNames, name)
+	}
+	return target
+}
+
+// Returns the names of the deployment targets, in the order they were created.
+func (dc *DependenciesCache) GetTargetNames() []string {
+	return dc.targetNames
+}
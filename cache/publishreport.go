@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+type PublishOutcome string
+
+const (
+	Published PublishOutcome = "published"
+	// The module was already published during the same run.
+	Skipped PublishOutcome = "skipped"
+	Failed  PublishOutcome = "failed"
+)
+
+// The result of publishing a module.
+type PublishRecord struct {
+	Id      string         `json:"id"`
+	Outcome PublishOutcome `json:"outcome"`
+	Error   string         `json:"error,omitempty"`
+	// The total size of the uploaded files.
+	Bytes      int64 `json:"bytes"`
+	DurationMs int64 `json:"durationMs"`
+	// The URL of the module version in the target repository, without the file extension.
+	TargetUrl string `json:"targetUrl,omitempty"`
+}
+
+// A machine-readable report of the publishing flow.
+type PublishReport struct {
+	Total     int             `json:"total"`
+	Published int             `json:"published"`
+	Skipped   int             `json:"skipped"`
+	Failed    int             `json:"failed"`
+	Modules   []PublishRecord `json:"modules"`
+	// The reports of the deployment targets, by the target name.
+	Targets map[string]*PublishReport `json:"targets,omitempty"`
+}
+
+func (dc *DependenciesCache) AddRecord(record PublishRecord) {
+	dc.records = append(dc.records, record)
+}
+
+func (dc *DependenciesCache) AddPublished(id, targetUrl string, bytes int64, duration time.Duration) {
+	dc.AddRecord(PublishRecord{Id: id, Outcome: Published, Bytes: bytes, DurationMs: duration.Milliseconds(), TargetUrl: targetUrl})
+}
+
+func (dc *DependenciesCache) AddSkipped(id, targetUrl string) {
+	dc.AddRecord(PublishRecord{Id: id, Outcome: Skipped, TargetUrl: targetUrl})
+}
+
+func (dc *DependenciesCache) AddFailed(id, targetUrl string, err error, duration time.Duration) {
+	dc.AddRecord(PublishRecord{Id: id, Outcome: Failed, Error: err.Error(), DurationMs: duration.Milliseconds(), TargetUrl: targetUrl})
+}
+
+func (dc *DependenciesCache) GetRecords() []PublishRecord {
+	return dc.records
+}
+
+// Returns the report of the modules published using the cache, in the order they were processed.
+// The summary includes the deployment targets, while their modules are listed in their own reports.
+func (dc *DependenciesCache) GetReport() *PublishReport {
+	report := &PublishReport{Total: dc.total, Modules: append([]PublishRecord{}, dc.records...)}
+	for _, record := range dc.records {
+		switch record.Outcome {
+		case Published:
+			report.Published++
+		case Skipped:
+			report.Skipped++
+		case Failed:
+			report.Failed++
+		}
+	}
+	for _, name := range dc.targetNames {
+		if report.Targets == nil {
+			report.Targets = make(map[string]*PublishReport)
+		}
+		targetReport := dc.targets[name].GetReport()
+		report.Targets[name] = targetReport
+		report.Total += targetReport.Total
+		report.Published += targetReport.Published
+		report.Skipped += targetReport.Skipped
+		report.Failed += targetReport.Failed
+	}
+	return report
+}
+
+// Writes the report as JSON to the provided path.
+func (dc *DependenciesCache) WriteReport(path string) error {
+	content, err := json.MarshalIndent(dc.GetReport(), "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(ioutil.WriteFile(path, content, 0644))
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPublishReport(t *testing.T) {
+	cache := DependenciesCache{}
+	cache.IncrementTotal(3)
+	cache.AddPublished("github.com/jfrog/a:v1.0.0", "http://localhost/api/go/go-local/github.com/jfrog/a/@v/v1.0.0", 100, 2*time.Second)
+	cache.AddSkipped("github.com/jfrog/a:v1.0.0", "http://localhost/api/go/go-local/github.com/jfrog/a/@v/v1.0.0")
+	cache.AddFailed("github.com/jfrog/b:v1.0.0", "", errors.New("Artifactory response: 500"), time.Second)
+
+	tempDir, err := ioutil.TempDir("", "publishReport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	reportPath := filepath.Join(tempDir, "report.json")
+	if err = cache.WriteReport(reportPath); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := &PublishReport{}
+	if err = json.Unmarshal(content, report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 3 || report.Published != 1 || report.Skipped != 1 || report.Failed != 1 || len(report.Modules) != 3 {
+		t.Error("Unexpected report summary:", string(content))
+	}
+	expected := PublishRecord{Id: "github.com/jfrog/a:v1.0.0", Outcome: Published, Bytes: 100, DurationMs: 2000, TargetUrl: "http://localhost/api/go/go-local/github.com/jfrog/a/@v/v1.0.0"}
+	if report.Modules[0] != expected {
+		t.Errorf("Expected %v, got %v", expected, report.Modules[0])
+	}
+	if report.Modules[2].Error != "Artifactory response: 500" {
+		t.Error("Unexpected error:", report.Modules[2].Error)
+	}
+}
+
+func TestPublishReportTargets(t *testing.T) {
+	cache := DependenciesCache{}
+	primary := cache.GetTarget("http://primary/go-local")
+	primary.IncrementTotal(1)
+	primary.AddPublished("github.com/jfrog/a:v1.0.0", "http://primary/api/go/go-local/github.com/jfrog/a/@v/v1.0.0", 100, time.Second)
+	dr := cache.GetTarget("http://dr/go-local")
+	dr.IncrementTotal(1)
+	dr.AddFailed("github.com/jfrog/a:v1.0.0", "http://dr/api/go/go-local/github.com/jfrog/a/@v/v1.0.0", errors.New("Artifactory response: 500"), time.Second)
+
+	if cache.GetTarget("http://primary/go-local") != primary {
+		t.Error("Expected the existing target cache to be returned")
+	}
+	names := cache.GetTargetNames()
+	if len(names) != 2 || names[0] != "http://primary/go-local" || names[1] != "http://dr/go-local" {
+		t.Error("Unexpected target names:", names)
+	}
+	report := cache.GetReport()
+	if report.Total != 2 || report.Published != 1 || report.Failed != 1 || len(report.Modules) != 0 {
+		t.Errorf("Unexpected report summary: %+v", report)
+	}
+	if len(report.Targets) != 2 || report.Targets["http://primary/go-local"].Published != 1 || report.Targets["http://dr/go-local"].Failed != 1 {
+		t.Errorf("Unexpected target reports: %+v", report.Targets)
+	}
+}
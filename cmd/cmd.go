@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,7 +16,6 @@ import (
 	"github.com/jfrog/jfrog-client-go/auth"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
-	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
 // Used for masking basic auth credentials as part of a URL.
@@ -38,6 +36,13 @@ func (config *Cmd) GetCmd() (cmd *exec.Cmd) {
 	cmdStr = append(cmdStr, config.CommandFlags...)
 	cmd = exec.Command(cmdStr[0], cmdStr[1:]...)
 	cmd.Dir = config.Dir
+	// The environment variables are set on the command only, rather than on the current process.
+	if len(config.Env) > 0 {
+		cmd.Env = os.Environ()
+		for key, value := range config.Env {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
 	return
 }
 
@@ -58,6 +63,7 @@ type Cmd struct {
 	Command      []string
 	CommandFlags []string
 	Dir          string
+	Env          map[string]string
 	StrWriter    io.WriteCloser
 	ErrWriter    io.WriteCloser
 }
@@ -72,29 +78,63 @@ func GetGoVersion() (string, error) {
 	return output, errorutils.CheckError(err)
 }
 
-func RunGo(goArg []string, server auth.ServiceDetails, repo string, noFallback bool) error {
+// Runs the go command with GOPROXY set to the Go repository in Artifactory.
+// If dependencies checks are set in the options, such as a policy, they are run on the dependencies of the project in the current directory
+// after the command succeeds, and the command is considered successful only if the checks pass.
+func RunGo(goArg []string, server auth.ServiceDetails, repo string, noFallback bool, options ...utils.Option) error {
 	utils.SetGoProxyWithApi(repo, server, noFallback)
+	opts := utils.NewOptions(options...)
+	err := runGo(goArg, nil, opts)
+	if err != nil || len(opts.DependenciesChecks) == 0 {
+		return err
+	}
+	_, err = GetDependenciesList("", options...)
+	return err
+}
 
+// RunGoWithProxyUrl runs the go command with GOPROXY set to the provided URL, such as the URL of a local utils.ProxyHandler.
+// Unlike RunGo, GOPROXY is set for the go command only, and not for the current process.
+func RunGoWithProxyUrl(goArg []string, proxyUrl string, noFallback bool, options ...utils.Option) error {
+	// If noFallback=false, missing packages will be fetched directly from VCS
+	if !noFallback {
+		proxyUrl += "|direct"
+	}
+	return runGo(goArg, map[string]string{utils.GOPROXY: proxyUrl}, utils.NewOptions(options...))
+}
+
+func runGo(goArg []string, env map[string]string, opts *utils.Options) error {
+	observer := opts.GetObserver()
+	event := utils.ProgressEvent{Operation: utils.GoOperation, ModuleId: strings.Join(goArg, " ")}
+	observer.ModuleStarted(event)
+	errorOut, err := runGoCmd(goArg, env)
+	err = classifyGoError(errorOut, err, getGoProxySetting(env))
+	utils.NotifyFinished(observer, event, err)
+	return err
+}
+
+// Runs the go command and returns its error output.
+func runGoCmd(goArg []string, env map[string]string) (errorOut string, err error) {
 	goCmd, err := NewCmd()
 	if err != nil {
-		return err
+		return
 	}
 	goCmd.Command = goArg
+	goCmd.Env = env
 	err = prepareRegExp()
 	if err != nil {
-		return err
+		return
 	}
 
 	performPasswordMask, err := shouldMaskPassword()
 	if err != nil {
-		return err
+		return
 	}
 	if performPasswordMask {
-		_, _, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, true, protocolRegExp)
+		_, errorOut, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, true, protocolRegExp)
 	} else {
-		_, _, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, true)
+		_, errorOut, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, true)
 	}
-	return errorutils.CheckError(err)
+	return errorOut, errorutils.CheckError(err)
 }
 
 // GetGOPATH returns the location of the GOPATH
@@ -112,24 +152,61 @@ func getGOPATH() (string, error) {
 }
 
 // Using go mod download {dependency} command to download the dependency
-func DownloadDependency(dependencyName string) error {
+func DownloadDependency(dependencyName string, options ...utils.Option) error {
+	opts := utils.NewOptions(options...)
 	goCmd, err := NewCmd()
 	if err != nil {
 		return err
 	}
-	log.Debug("Running go mod download -json", dependencyName)
+	opts.GetLogger().Debug("Running go mod download -json", dependencyName)
 	goCmd.Command = []string{"mod", "download", "-json", dependencyName}
-	return errorutils.CheckError(gofrogcmd.RunCmd(goCmd))
+	output := &outputRecorder{writer: os.Stdout}
+	errorOutput := &outputRecorder{writer: os.Stderr}
+	goCmd.StrWriter = output
+	goCmd.ErrWriter = errorOutput
+	observer := opts.GetObserver()
+	event := utils.ProgressEvent{Operation: utils.DownloadOperation, ModuleId: dependencyName}
+	observer.ModuleStarted(event)
+	err = errorutils.CheckError(gofrogcmd.RunCmd(goCmd))
+	err = classifyGoError(errorOutput.String()+"\n"+getDownloadJsonErrors(output.String()), err, getGoProxySetting(nil))
+	utils.NotifyFinished(observer, event, err)
+	return err
 }
 
 // Runs 'go list -m' command and returns module name
-func GetModuleNameByDir(projectDir string) (string, error) {
+func GetModuleNameByDir(projectDir string, options ...utils.Option) (string, error) {
+	opts := utils.NewOptions(options...)
+	var err error
+	if projectDir == "" {
+		projectDir, err = GetProjectRoot()
+		if err != nil {
+			return "", err
+		}
+	}
+	// In workspace mode, 'go list -m' lists all the workspace members.
+	workspace, err := GetWorkspace(projectDir)
+	if err != nil {
+		return "", err
+	}
+	if workspace != nil {
+		name, err := getWorkspaceMemberNameByDir(workspace, projectDir)
+		if err != nil || name != "" {
+			return name, err
+		}
+	}
 	cmdArgs, err := getListCmdArgs()
 	if err != nil {
 		return "", err
 	}
 	cmdArgs = append(cmdArgs, "-m")
-	output, err := runDependenciesCmd(projectDir, cmdArgs)
+	var output string
+	if workspace != nil {
+		// A module inside the workspace tree which is not a member of the workspace is listed in module mode.
+		opts.GetLogger().Debug(fmt.Sprintf("%s is not a member of the workspace %s. Running 'go %s' with %s=off", projectDir, workspace.GoWorkPath, strings.Join(cmdArgs, " "), goWorkEnv))
+		output, err = runModuleDependenciesCmd(projectDir, cmdArgs, map[string]string{goWorkEnv: "off"}, opts)
+	} else {
+		output, err = runDependenciesCmd(projectDir, cmdArgs, opts)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -151,21 +228,41 @@ func getListCmdArgs() (cmdArgs []string, err error) {
 }
 
 // Runs go list -f {{with .Module}}{{.Path}}:{{.Version}}{{end}} all command and returns map of the dependencies
-func GetDependenciesList(projectDir string) (map[string]bool, error) {
+// The dependencies checks of the options are run on the dependencies, and an error is returned if one of them fails.
+func GetDependenciesList(projectDir string, options ...utils.Option) (map[string]bool, error) {
+	cmdArgs, err := getListCmdArgs()
+	if err != nil {
+		return nil, err
+	}
+	opts := utils.NewOptions(options...)
+	output, err := runDependenciesCmd(projectDir, append(cmdArgs, "-f", "{{with .Module}}{{.Path}}@{{.Version}}{{end}}", "all"), opts)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := listToMap(output)
+	if err = opts.RunDependenciesChecks(dependencies); err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// Runs 'go list -m all' and returns a map of the modules in the build list, in the format returned by GetDependenciesList.
+// Unlike GetDependenciesList, the packages aren't loaded, so only the go.mod files of the modules are downloaded, rather than their zips.
+func GetBuildList(projectDir string, options ...utils.Option) (map[string]bool, error) {
 	cmdArgs, err := getListCmdArgs()
 	if err != nil {
 		return nil, err
 	}
-	output, err := runDependenciesCmd(projectDir, append(cmdArgs, "-f", "{{with .Module}}{{.Path}}@{{.Version}}{{end}}", "all"))
+	output, err := runDependenciesCmd(projectDir, append(cmdArgs, "-m", "-f", "{{.Path}}@{{.Version}}", "all"), utils.NewOptions(options...))
 	if err != nil {
 		return nil, err
 	}
-	return listToMap(output), errorutils.CheckError(err)
+	return listToMap(output), nil
 }
 
 // Runs 'go mod graph' command and returns map that maps dependencies to their child dependencies slice
-func GetDependenciesGraph(projectDir string) (map[string][]string, error) {
-	output, err := runDependenciesCmd(projectDir, []string{"mod", "graph"})
+func GetDependenciesGraph(projectDir string, options ...utils.Option) (map[string][]string, error) {
+	output, err := runDependenciesCmd(projectDir, []string{"mod", "graph"}, utils.NewOptions(options...))
 	if err != nil {
 		return nil, err
 	}
@@ -173,8 +270,8 @@ func GetDependenciesGraph(projectDir string) (map[string][]string, error) {
 }
 
 // Common function to run dependencies command for list or graph commands
-func runDependenciesCmd(projectDir string, commandArgs []string) (string, error) {
-	log.Info(fmt.Sprintf("Running 'go %s' in %s", strings.Join(commandArgs, " "), projectDir))
+func runDependenciesCmd(projectDir string, commandArgs []string, opts *utils.Options) (string, error) {
+	opts.GetLogger().Info(fmt.Sprintf("Running 'go %s' in %s", strings.Join(commandArgs, " "), projectDir))
 	var err error
 	if projectDir == "" {
 		projectDir, err = GetProjectRoot()
@@ -182,23 +279,111 @@ func runDependenciesCmd(projectDir string, commandArgs []string) (string, error)
 			return "", err
 		}
 	}
-	// Read and store the details of the go.mod and go.sum files,
-	// because they may change by the 'go mod graph' or 'go list' commands.
-	modFileContent, modFileStat, err := GetFileDetails(filepath.Join(projectDir, "go.mod"))
+	goWorkPath, err := FindGoWork(projectDir)
 	if err != nil {
-		log.Info("Dependencies were not collected for this build, since go.mod could not be found in", projectDir)
+		return "", err
+	}
+	if goWorkPath != "" {
+		return runWorkspaceDependenciesCmd(projectDir, goWorkPath, commandArgs, opts)
+	}
+	return runModuleDependenciesCmd(projectDir, commandArgs, nil, opts)
+}
+
+// Runs a dependencies command in module mode, using the go.mod file in projectDir. The env is added to the environment of the command.
+func runModuleDependenciesCmd(projectDir string, commandArgs []string, env map[string]string, opts *utils.Options) (string, error) {
+	exists, err := fileutils.IsFileExists(filepath.Join(projectDir, "go.mod"), false)
+	if err != nil || !exists {
+		opts.GetLogger().Info("Dependencies were not collected for this build, since go.mod could not be found in", projectDir)
 		return "", nil
 	}
-	sumFileContent, sumFileStat, err := GetGoSum(projectDir)
-	if len(sumFileContent) > 0 && sumFileStat != nil {
-		defer RestoreSumFile(projectDir, sumFileContent, sumFileStat)
+	// The 'go mod graph' and 'go list' commands may modify the go.mod and go.sum files.
+	// To keep the project untouched, the commands run against a copy of these files, using the -modfile flag.
+	noModFileFlag, err := modFileFlagUnsupported()
+	if err != nil {
+		return "", err
+	}
+	if noModFileFlag {
+		return runDependenciesCmdWithRestore(projectDir, commandArgs, env, opts)
 	}
+	return runDependenciesCmdWithTempModFile(projectDir, commandArgs, env, opts)
+}
+
+// Copies the go.mod and go.sum files to a temp directory, and runs the command with the -modfile flag pointing to the copy.
+// The go command reads and writes the go.sum file located next to the mod file, so the project files are not accessed for writing.
+func runDependenciesCmdWithTempModFile(projectDir string, commandArgs []string, env map[string]string, opts *utils.Options) (output string, err error) {
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		e := fileutils.RemoveTempDir(tempDir)
+		if err == nil {
+			err = e
+		}
+	}()
+	for _, fileName := range []string{"go.mod", "go.sum"} {
+		exists, err := fileutils.IsFileExists(filepath.Join(projectDir, fileName), false)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			err = fileutils.CopyFile(tempDir, filepath.Join(projectDir, fileName))
+			if err != nil {
+				return "", errorutils.CheckError(err)
+			}
+		}
+	}
+	return runGoCmdInDir(projectDir, addModFileFlag(commandArgs, filepath.Join(tempDir, "go.mod")), env, opts)
+}
+
+// Runs the command in the project directory and restores the go.mod and go.sum files afterwards.
+// Used with go versions which do not support the -modfile flag.
+func runDependenciesCmdWithRestore(projectDir string, commandArgs []string, env map[string]string, opts *utils.Options) (output string, err error) {
+	// Read and store the details of the go.mod and go.sum files,
+	// because they may change by the 'go mod graph' or 'go list' commands.
+	var snapshots []*fileSnapshot
+	for _, fileName := range []string{"go.mod", "go.sum"} {
+		snapshot, err := takeFileSnapshot(filepath.Join(projectDir, fileName))
+		if err != nil {
+			return "", err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	// Restore the the go.mod and go.sum files, to make sure they stay the same as before running the command.
+	defer func() {
+		for _, snapshot := range snapshots {
+			e := snapshot.restore(opts.GetLogger())
+			if err == nil {
+				err = e
+			}
+		}
+	}()
+	return runGoCmdInDir(projectDir, commandArgs, env, opts)
+}
+
+// Adds the -modfile flag after the subcommand (for example 'go mod graph -modfile=path').
+func addModFileFlag(commandArgs []string, modFilePath string) []string {
+	flagIndex := len(commandArgs)
+	for i, arg := range commandArgs {
+		if strings.HasPrefix(arg, "-") {
+			flagIndex = i
+			break
+		}
+	}
+	args := append([]string{}, commandArgs[:flagIndex]...)
+	args = append(args, "-modfile="+modFilePath)
+	return append(args, commandArgs[flagIndex:]...)
+}
+
+// Runs a go command in the provided directory and returns its output.
+func runGoCmdInDir(dir string, commandArgs []string, env map[string]string, opts *utils.Options) (string, error) {
 	goCmd, err := NewCmd()
 	if err != nil {
 		return "", err
 	}
 	goCmd.Command = commandArgs
-	goCmd.Dir = projectDir
+	goCmd.Dir = dir
+	goCmd.Env = env
 
 	err = prepareGlobalRegExp()
 	if err != nil {
@@ -216,24 +401,17 @@ func runDependenciesCmd(projectDir string, commandArgs []string) (string, error)
 		output, _, _, executionError = gofrogcmd.RunCmdWithOutputParser(goCmd, true)
 	}
 	if len(output) != 0 {
-		log.Debug(output)
+		opts.GetLogger().Debug(output)
 	}
 	if executionError != nil {
-		// If the command fails, the mod stays the same, therefore, don't need to be restored.
-		errorString := fmt.Sprintf("Failed running Go command: 'go %s' in %s with error: '%s'", strings.Join(commandArgs, " "), projectDir, executionError.Error())
+		errorString := fmt.Sprintf("Failed running Go command: 'go %s' in %s with error: '%s'", strings.Join(commandArgs, " "), dir, executionError.Error())
 		return "", errorutils.CheckError(errors.New(errorString))
 	}
-
-	// Restore the the go.mod and go.sum files, to make sure they stay the same as before
-	// running the "go mod graph" command.
-	err = ioutil.WriteFile(filepath.Join(projectDir, "go.mod"), modFileContent, modFileStat.Mode())
-	if err != nil {
-		return "", err
-	}
-	return output, err
+	return output, nil
 }
 
 // Returns the root dir where the go.mod located.
+// If a go.work file is found before a go.mod file, the workspace root dir is returned.
 func GetProjectRoot() (string, error) {
 	// Create a map to store all paths visited, to avoid running in circles.
 	visitedPaths := make(map[string]bool)
@@ -262,6 +440,11 @@ func GetProjectRoot() (string, error) {
 		if err != nil || exists {
 			return wd, err
 		}
+		// The root of a workspace may not include a go.mod file.
+		exists, err = fileutils.IsFileExists(filepath.Join(wd, goWorkFileName), false)
+		if err != nil || exists {
+			return wd, err
+		}
 
 		// If this the OS root, we can stop.
 		if wd == osRoot {
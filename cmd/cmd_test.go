@@ -1,15 +1,18 @@
 package cmd
 
 import (
+	"github.com/jfrog/gocmd/executers/utils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestListToMap(t *testing.T) {
@@ -126,7 +129,9 @@ func TestGetDependenciesList(t *testing.T) {
 		err = fileutils.MoveFile(filepath.Join(gomodPath, "go.sum"), filepath.Join(gomodPath, "go.sum.txt"))
 		assert.NoError(t, err)
 	}()
-	originSumFileContent, _, err := GetGoSum(gomodPath)
+	originSumFileContent, originSumFileStat, err := GetGoSum(gomodPath)
+	originModFileContent, originModFileStat, err := GetFileDetails(filepath.Join(gomodPath, "go.mod"))
+	assert.NoError(t, err)
 	err = fileutils.MoveFile(filepath.Join(gomodPath, "test.go.txt"), filepath.Join(gomodPath, "test.go"))
 	assert.NoError(t, err)
 	defer func() {
@@ -140,10 +145,16 @@ func TestGetDependenciesList(t *testing.T) {
 
 	// Since Go 1.16 'go list' command won't automatically update go.mod and go.sum.
 	// Check that we rollback changes properly.
-	newSumFileContent, _, err := GetGoSum(gomodPath)
+	newSumFileContent, newSumFileStat, err := GetGoSum(gomodPath)
 	if !reflect.DeepEqual(originSumFileContent, newSumFileContent) {
 		t.Errorf("go.sum has been modified and didn't rollback properly")
 	}
+	// The project files should not be written at all.
+	assert.Equal(t, originSumFileStat.ModTime(), newSumFileStat.ModTime())
+	newModFileContent, newModFileStat, err := GetFileDetails(filepath.Join(gomodPath, "go.mod"))
+	assert.NoError(t, err)
+	assert.Equal(t, originModFileContent, newModFileContent)
+	assert.Equal(t, originModFileStat.ModTime(), newModFileStat.ModTime())
 
 	expected := map[string]bool{
 		"golang.org/x/text@v0.3.3": true,
@@ -156,6 +167,56 @@ func TestGetDependenciesList(t *testing.T) {
 	}
 }
 
+func TestAddModFileFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"list", []string{"list", "-mod=mod", "-f", "{{.Path}}", "all"}, []string{"list", "-modfile=/tmp/go.mod", "-mod=mod", "-f", "{{.Path}}", "all"}},
+		{"list module", []string{"list", "-m"}, []string{"list", "-modfile=/tmp/go.mod", "-m"}},
+		{"mod graph", []string{"mod", "graph"}, []string{"mod", "graph", "-modfile=/tmp/go.mod"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, addModFileFlag(test.args, "/tmp/go.mod"))
+		})
+	}
+}
+
+func TestFileSnapshotRestore(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	tempDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer fileutils.RemoveTempDir(tempDir)
+
+	// A modified file is restored with its original modification time.
+	modPath := filepath.Join(tempDir, "go.mod")
+	assert.NoError(t, ioutil.WriteFile(modPath, []byte("module a\n"), 0644))
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	assert.NoError(t, os.Chtimes(modPath, modTime, modTime))
+	modSnapshot, err := takeFileSnapshot(modPath)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(modPath, []byte("module b\n"), 0644))
+
+	// A file created after the snapshot is removed.
+	sumPath := filepath.Join(tempDir, "go.sum")
+	sumSnapshot, err := takeFileSnapshot(sumPath)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(sumPath, []byte("sum"), 0644))
+
+	assert.NoError(t, modSnapshot.restore(utils.GetGlobalLogger()))
+	assert.NoError(t, sumSnapshot.restore(utils.GetGlobalLogger()))
+	content, stat, err := GetFileDetails(modPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "module a\n", string(content))
+	assert.Equal(t, modTime, stat.ModTime())
+	assert.False(t, fileutils.IsPathExists(sumPath, false))
+	files, err := ioutil.ReadDir(tempDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
 func TestParseGoPathWindows(t *testing.T) {
 	log.SetLogger(log.NewLogger(log.DEBUG, nil))
 	if runtime.GOOS != "windows" {
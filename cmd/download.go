@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	gofrogcmd "github.com/jfrog/gofrog/io"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// The result of downloading a module, as reported by 'go mod download -json'.
+type DownloadedModule struct {
+	Path     string
+	Version  string
+	Info     string
+	GoMod    string
+	Zip      string
+	Dir      string
+	Sum      string
+	GoModSum string
+	Error    string
+	// The error of the download, converted to a typed error when possible. Nil if the module was downloaded.
+	Err error `json:"-"`
+}
+
+// Downloads the modules (in the path@version format) by running 'go mod download -json' with the provided environment variables.
+// Unlike DownloadDependency, a module which fails to download doesn't fail the others. Its error is returned in its result instead.
+func DownloadModules(modules []string, env map[string]string, options ...utils.Option) ([]DownloadedModule, error) {
+	if len(modules) == 0 {
+		return nil, nil
+	}
+	goCmd, err := NewCmd()
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = append([]string{"mod", "download", "-json"}, modules...)
+	goCmd.Env = env
+	err = prepareGlobalRegExp()
+	if err != nil {
+		return nil, err
+	}
+	performPasswordMask, err := shouldMaskPassword()
+	if err != nil {
+		return nil, err
+	}
+	utils.NewOptions(options...).GetLogger().Debug("Running go mod download -json", strings.Join(modules, " "))
+	var output, errorOut string
+	if performPasswordMask {
+		output, errorOut, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, false, protocolRegExp)
+	} else {
+		output, errorOut, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, false)
+	}
+	results, parseErr := parseDownloadedModules(output, getGoProxySetting(env))
+	if parseErr != nil || len(results) == 0 {
+		// The command failed before downloading the modules, for example because of an invalid argument.
+		if err != nil {
+			return nil, classifyGoError(errorOut, errorutils.CheckError(err), getGoProxySetting(env))
+		}
+		return nil, parseErr
+	}
+	return results, nil
+}
+
+// Parses the output of 'go mod download -json', and converts the errors of the modules to typed errors.
+func parseDownloadedModules(output string, setting goProxySetting) ([]DownloadedModule, error) {
+	var results []DownloadedModule
+	decoder := json.NewDecoder(strings.NewReader(output))
+	for {
+		var downloaded DownloadedModule
+		err := decoder.Decode(&downloaded)
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return results, errorutils.CheckError(err)
+		}
+		if downloaded.Error != "" {
+			downloaded.Err = classifyGoError(downloaded.Error, errors.New(downloaded.Error), setting)
+		}
+		results = append(results, downloaded)
+	}
+}
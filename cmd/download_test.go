@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDownloadedModules(t *testing.T) {
+	output := `{
+	"Path": "rsc.io/quote",
+	"Version": "v1.5.2",
+	"Zip": "/cache/download/rsc.io/quote/@v/v1.5.2.zip"
+}
+{
+	"Path": "rsc.io/sampler",
+	"Version": "v9.9.9",
+	"Error": "rsc.io/sampler@v9.9.9: reading ` + testProxyUrl + `/rsc.io/sampler/@v/v9.9.9.info: 404 Not Found"
+}
+`
+	results, err := parseDownloadedModules(output, goProxySetting{proxies: []string{testProxyUrl}})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "/cache/download/rsc.io/quote/@v/v1.5.2.zip", results[0].Zip)
+		assert.NoError(t, results[0].Err)
+		var notFoundErr *utils.ModuleNotFoundError
+		if assert.True(t, errors.As(results[1].Err, &notFoundErr)) {
+			assert.Equal(t, "rsc.io/sampler", notFoundErr.Module)
+			assert.Equal(t, "v9.9.9", notFoundErr.Version)
+		}
+	}
+
+	_, err = parseDownloadedModules("go: invalid output", goProxySetting{})
+	assert.Error(t, err)
+}
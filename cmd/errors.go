@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/gocmd/executers/utils"
+)
+
+var (
+	// Matches the errors the go command reports for a module version, such as "github.com/foo/bar@v1.0.0: <message>".
+	goModuleErrorRegExp = regexp.MustCompile(`([^\s@:]+)@([^\s:]+?)(/go\.mod)?: (.+)$`)
+	// Matches the message of a failed request, such as "reading https://host/path: 404 Not Found".
+	goReadingStatusRegExp = regexp.MustCompile(`^reading (\S+): (\d{3})\b`)
+	// Matches the lines which follow a checksum mismatch error, such as "downloaded: h1:..." and "go.sum: h1:...".
+	goChecksumLineRegExp = regexp.MustCompile(`^\s*(downloaded|go\.sum|sum\.golang\.org):\s+(\S+)`)
+)
+
+// The GOPROXY setting used by a go command.
+type goProxySetting struct {
+	proxies  []string
+	fallback bool
+}
+
+// Returns the GOPROXY setting of a go command, which runs with the provided environment variables.
+func getGoProxySetting(env map[string]string) goProxySetting {
+	goProxy, ok := env[utils.GOPROXY]
+	if !ok {
+		goProxy = os.Getenv(utils.GOPROXY)
+	}
+	setting := goProxySetting{}
+	for _, entry := range strings.FieldsFunc(goProxy, func(r rune) bool { return r == ',' || r == '|' }) {
+		switch entry {
+		case "direct":
+			setting.fallback = true
+		case "off":
+		default:
+			setting.proxies = append(setting.proxies, entry)
+		}
+	}
+	return setting
+}
+
+// Returns true if the URL, as reported by the go command, belongs to one of the proxies.
+// The URLs are compared without credentials, which are masked in the output.
+func (setting goProxySetting) isProxyUrl(rawUrl string) bool {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+	for _, proxy := range setting.proxies {
+		proxyUrl, err := url.Parse(proxy)
+		if err != nil {
+			continue
+		}
+		if proxyUrl.Host == parsedUrl.Host && strings.HasPrefix(parsedUrl.Path, strings.TrimSuffix(proxyUrl.Path, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Converts the error of a go command to a typed error, according to the error output of the command:
+// utils.AuthenticationError, utils.ModuleNotFoundError or utils.ModuleGoneError if Artifactory failed the request,
+// utils.ChecksumMismatchError if the checksum of a downloaded module doesn't match go.sum,
+// and utils.BothSourcesError if the module couldn't be retrieved from VCS after falling back from Artifactory.
+// If the output includes no known failure, err is returned.
+func classifyGoError(errorOut string, err error, setting goProxySetting) error {
+	if err == nil {
+		return nil
+	}
+	lines := strings.Split(errorOut, "\n")
+	// The failures of the proxies which have no typed error, by <module path>@<version>.
+	proxyErrs := map[string]error{}
+	for i, line := range lines {
+		match := goModuleErrorRegExp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		modulePath, version, message := match[1], match[2], match[4]
+		if strings.Contains(message, "checksum mismatch") {
+			return newChecksumMismatchError(modulePath, version, match[3] != "" || strings.Contains(message, "go.mod"), lines[i+1:])
+		}
+		if statusMatch := goReadingStatusRegExp.FindStringSubmatch(message); statusMatch != nil && setting.isProxyUrl(statusMatch[1]) {
+			statusCode, _ := strconv.Atoi(statusMatch[2])
+			if typedErr := utils.NewStatusError(statusCode, modulePath, version, statusMatch[1]); typedErr != nil {
+				return typedErr
+			}
+			proxyErrs[modulePath+"@"+version] = errors.New(message)
+			continue
+		}
+		// The go command tries the direct source only after the proxies failed.
+		if setting.fallback && len(setting.proxies) > 0 {
+			vcsErr := &utils.VcsFallbackError{Module: modulePath, Version: version, Err: errors.New(message)}
+			return &utils.BothSourcesError{Module: modulePath, Version: version, ArtifactoryErr: proxyErrs[modulePath+"@"+version], VcsErr: vcsErr}
+		}
+	}
+	return err
+}
+
+func newChecksumMismatchError(modulePath, version string, isGoMod bool, followingLines []string) error {
+	checksumErr := &utils.ChecksumMismatchError{Module: modulePath, Version: version, File: "zip"}
+	if isGoMod {
+		checksumErr.File = "go.mod"
+	}
+	for _, line := range followingLines {
+		match := goChecksumLineRegExp.FindStringSubmatch(line)
+		if match == nil {
+			break
+		}
+		if match[1] == "downloaded" {
+			checksumErr.Actual = match[2]
+		} else {
+			checksumErr.Expected = match[2]
+		}
+	}
+	return checksumErr
+}
+
+// Returns the errors reported in the output of 'go mod download -json', one per line.
+func getDownloadJsonErrors(output string) string {
+	var errorsOut []string
+	decoder := json.NewDecoder(strings.NewReader(output))
+	for {
+		var downloaded struct{ Error string }
+		if decoder.Decode(&downloaded) != nil {
+			break
+		}
+		if downloaded.Error != "" {
+			errorsOut = append(errorsOut, downloaded.Error)
+		}
+	}
+	return strings.Join(errorsOut, "\n")
+}
+
+// Writes the output of a command to the provided writer, and records it for classifyGoError.
+type outputRecorder struct {
+	bytes.Buffer
+	writer io.Writer
+}
+
+func (recorder *outputRecorder) Write(p []byte) (int, error) {
+	recorder.Buffer.Write(p)
+	return recorder.writer.Write(p)
+}
+
+func (recorder *outputRecorder) Close() error {
+	return nil
+}
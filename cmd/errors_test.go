@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testProxyUrl = "https://acme.jfrog.io/artifactory/api/go/go-virtual"
+
+func TestGetGoProxySetting(t *testing.T) {
+	setting := getGoProxySetting(map[string]string{utils.GOPROXY: testProxyUrl + "|direct"})
+	assert.Equal(t, []string{testProxyUrl}, setting.proxies)
+	assert.True(t, setting.fallback)
+	assert.True(t, setting.isProxyUrl("https://***@acme.jfrog.io/artifactory/api/go/go-virtual/rsc.io/quote/@v/list"))
+	assert.False(t, setting.isProxyUrl("https://rsc.io/quote?go-get=1"))
+
+	setting = getGoProxySetting(map[string]string{utils.GOPROXY: testProxyUrl})
+	assert.False(t, setting.fallback)
+}
+
+func TestClassifyGoError(t *testing.T) {
+	goErr := errors.New("exit status 1")
+	noFallback := goProxySetting{proxies: []string{testProxyUrl}}
+	fallback := goProxySetting{proxies: []string{testProxyUrl}, fallback: true}
+
+	// Artifactory failures.
+	err := classifyGoError("go: downloading rsc.io/quote v1.5.2\ngo: rsc.io/quote@v1.5.2: reading "+testProxyUrl+"/rsc.io/quote/@v/v1.5.2.info: 401 Unauthorized\n", goErr, noFallback)
+	var authErr *utils.AuthenticationError
+	if assert.True(t, errors.As(err, &authErr)) {
+		assert.Equal(t, "rsc.io/quote", authErr.Module)
+		assert.Equal(t, "v1.5.2", authErr.Version)
+		assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+	}
+	err = classifyGoError("go: rsc.io/quote@v1.5.2 requires\n\trsc.io/sampler@v1.3.0: reading "+testProxyUrl+"/rsc.io/sampler/@v/v1.3.0.mod: 404 Not Found\n", goErr, noFallback)
+	var notFoundErr *utils.ModuleNotFoundError
+	if assert.True(t, errors.As(err, &notFoundErr)) {
+		assert.Equal(t, "rsc.io/sampler", notFoundErr.Module)
+	}
+
+	// VCS failure after falling back from Artifactory.
+	err = classifyGoError("go: github.com/jfrog/no-such-module@v1.0.0: git ls-remote -q origin in /tmp: exit status 128\n", goErr, fallback)
+	var bothErr *utils.BothSourcesError
+	var vcsErr *utils.VcsFallbackError
+	if assert.True(t, errors.As(err, &bothErr)) && assert.True(t, errors.As(bothErr.VcsErr, &vcsErr)) {
+		assert.Equal(t, "github.com/jfrog/no-such-module", vcsErr.Module)
+		assert.Contains(t, vcsErr.Error(), "exit status 128")
+	}
+	// The VCS error is unwrapped when the Artifactory error is unknown.
+	vcsErr = nil
+	if assert.True(t, errors.As(err, &vcsErr)) {
+		assert.Equal(t, "v1.0.0", vcsErr.Version)
+	}
+	// The failure of the proxy is kept as the Artifactory error.
+	err = classifyGoError("go: github.com/jfrog/no-such-module@v1.0.0: reading "+testProxyUrl+"/github.com/jfrog/no-such-module/@v/v1.0.0.info: 502 Bad Gateway\ngo: github.com/jfrog/no-such-module@v1.0.0: git ls-remote -q origin in /tmp: exit status 128\n", goErr, fallback)
+	if assert.True(t, errors.As(err, &bothErr)) && assert.Error(t, bothErr.ArtifactoryErr) {
+		assert.Contains(t, bothErr.ArtifactoryErr.Error(), "502 Bad Gateway")
+		assert.True(t, errors.As(bothErr.VcsErr, &vcsErr))
+	}
+	// Without fallback, the error is returned as is.
+	assert.Equal(t, goErr, classifyGoError("go: github.com/jfrog/no-such-module@v1.0.0: invalid version\n", goErr, noFallback))
+
+	// Checksum mismatch.
+	err = classifyGoError("verifying rsc.io/quote@v1.5.2/go.mod: checksum mismatch\n\tdownloaded: h1:actual=\n\tgo.sum:     h1:expected=\n\nSECURITY ERROR\n", goErr, fallback)
+	var checksumErr *utils.ChecksumMismatchError
+	if assert.True(t, errors.As(err, &checksumErr)) {
+		assert.Equal(t, utils.ChecksumMismatchError{Module: "rsc.io/quote", Version: "v1.5.2", File: "go.mod", Expected: "h1:expected=", Actual: "h1:actual="}, *checksumErr)
+	}
+
+	// Unknown failures and successes.
+	assert.Equal(t, goErr, classifyGoError("go: unknown command\n", goErr, fallback))
+	assert.NoError(t, classifyGoError("", nil, fallback))
+}
+
+func TestGetDownloadJsonErrors(t *testing.T) {
+	output := `{
+	"Path": "rsc.io/quote",
+	"Version": "v9.9.9",
+	"Error": "rsc.io/quote@v9.9.9: reading https://proxy.golang.org/rsc.io/quote/@v/v9.9.9.info: 404 Not Found"
+}
+{
+	"Path": "rsc.io/sampler",
+	"Version": "v1.3.0"
+}
+`
+	assert.Equal(t, "rsc.io/quote@v9.9.9: reading https://proxy.golang.org/rsc.io/quote/@v/v9.9.9.info: 404 Not Found", getDownloadJsonErrors(output))
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// Represents a module located in the local file system.
+type LocalModule struct {
+	// The module path, as declared in the go.mod file.
+	Path string
+	// The absolute path to the module root directory, where the go.mod file is located.
+	Dir string
+}
+
+// Represents a failure of an operation on one module out of many.
+type ModuleError struct {
+	Module LocalModule
+	Err    error
+}
+
+func (moduleError *ModuleError) Error() string {
+	// The path of a module which its go.mod file couldn't be parsed is unknown.
+	if moduleError.Module.Path == "" {
+		return fmt.Sprintf("%s: %s", moduleError.Module.Dir, moduleError.Err.Error())
+	}
+	return fmt.Sprintf("%s (%s): %s", moduleError.Module.Path, moduleError.Module.Dir, moduleError.Err.Error())
+}
+
+func (moduleError *ModuleError) Unwrap() error {
+	return moduleError.Err
+}
+
+// Aggregates the failures of an operation on many modules.
+type ModulesErrors []*ModuleError
+
+func (modulesErrors ModulesErrors) Error() string {
+	var messages []string
+	for _, moduleError := range modulesErrors {
+		messages = append(messages, moduleError.Error())
+	}
+	return fmt.Sprintf("%d modules failed:\n%s", len(modulesErrors), strings.Join(messages, "\n"))
+}
+
+// Returns nil if there are no errors, so the result can be returned as an error.
+func (modulesErrors ModulesErrors) ErrorOrNil() error {
+	if len(modulesErrors) == 0 {
+		return nil
+	}
+	return modulesErrors
+}
+
+// The dependencies of a module, in the format returned by GetDependenciesList.
+type ModuleDependencies struct {
+	Module       LocalModule
+	Dependencies map[string]bool
+}
+
+// Returns all the modules under rootDir, including rootDir itself, sorted by their directories.
+// Like the go command, vendor and testdata directories, and directories which their names begin with "." or "_" are skipped.
+// Each module contains the packages under its directory, excluding nested modules.
+// The go.mod files which couldn't be parsed are reported in the returned ModulesErrors, after the rest of the modules were found, and the found modules are still returned.
+func FindModules(rootDir string, options ...utils.Option) ([]LocalModule, error) {
+	rootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var modules []LocalModule
+	var modulesErrors ModulesErrors
+	err = filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != rootDir && shouldSkipDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() != "go.mod" {
+			return nil
+		}
+		goMod, err := utils.ReadGoMod(path)
+		if err == nil && goMod.Module == "" {
+			err = errorutils.CheckError(errors.New(fmt.Sprintf("Could not find the module path in %s", path)))
+		}
+		if err != nil {
+			modulesErrors = append(modulesErrors, &ModuleError{Module: LocalModule{Dir: filepath.Dir(path)}, Err: err})
+			return nil
+		}
+		modules = append(modules, LocalModule{Path: goMod.Module, Dir: filepath.Dir(path)})
+		return nil
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Dir < modules[j].Dir
+	})
+	utils.NewOptions(options...).GetLogger().Debug(fmt.Sprintf("Found %d modules under %s", len(modules), rootDir))
+	return modules, modulesErrors.ErrorOrNil()
+}
+
+func shouldSkipDir(name string) bool {
+	return name == vendorDirName || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// Runs GetDependenciesList for each of the modules.
+// The dependencies of the modules which succeeded are returned, along with a ModulesErrors for the modules which failed.
+func GetModulesDependenciesList(modules []LocalModule, options ...utils.Option) ([]ModuleDependencies, error) {
+	logger := utils.NewOptions(options...).GetLogger()
+	var results []ModuleDependencies
+	var modulesErrors ModulesErrors
+	for _, module := range modules {
+		dependencies, err := GetDependenciesList(module.Dir, options...)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed collecting the dependencies of %s: %s", module.Path, err.Error()))
+			modulesErrors = append(modulesErrors, &ModuleError{Module: module, Err: err})
+			continue
+		}
+		results = append(results, ModuleDependencies{Module: module, Dependencies: dependencies})
+	}
+	return results, modulesErrors.ErrorOrNil()
+}
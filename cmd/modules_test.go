@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindModules(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	rootDir, err := filepath.Abs(filepath.Join("testdata", "multimodule"))
+	assert.NoError(t, err)
+	modules, err := FindModules(rootDir)
+	assert.NoError(t, err)
+	expected := []LocalModule{
+		{Path: "example.com/root", Dir: rootDir},
+		{Path: "example.com/broken", Dir: filepath.Join(rootDir, "broken")},
+		{Path: "example.com/sub", Dir: filepath.Join(rootDir, "sub")},
+	}
+	assert.Equal(t, expected, modules)
+}
+
+func TestFindModulesInvalidGoMod(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	rootDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer fileutils.RemoveTempDir(rootDir)
+	goMods := map[string]string{
+		"go.mod":          "module example.com/root\n",
+		"a/go.mod":        "module example.com/a\nrequire (\n",
+		"b/go.mod":        "go 1.15\n",
+		"c/go.mod":        "module example.com/c\n",
+		"a/nested/go.mod": "module example.com/a/nested\n",
+	}
+	for path, content := range goMods {
+		path = filepath.Join(rootDir, filepath.FromSlash(path))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	// The invalid go.mod files are reported, and the rest of the modules are still found.
+	modules, err := FindModules(rootDir)
+	var modulesErrors ModulesErrors
+	if assert.True(t, errors.As(err, &modulesErrors)) && assert.Len(t, modulesErrors, 2) {
+		assert.Equal(t, LocalModule{Dir: filepath.Join(rootDir, "a")}, modulesErrors[0].Module)
+		assert.Equal(t, LocalModule{Dir: filepath.Join(rootDir, "b")}, modulesErrors[1].Module)
+		assert.Contains(t, modulesErrors[1].Error(), "Could not find the module path")
+	}
+	assert.Equal(t, []LocalModule{
+		{Path: "example.com/root", Dir: rootDir},
+		{Path: "example.com/a/nested", Dir: filepath.Join(rootDir, "a", "nested")},
+		{Path: "example.com/c", Dir: filepath.Join(rootDir, "c")},
+	}, modules)
+}
+
+func TestGetModulesDependenciesList(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	modules, err := FindModules(filepath.Join("testdata", "multimodule"))
+	assert.NoError(t, err)
+	results, err := GetModulesDependenciesList(modules)
+
+	// The broken module fails, but the other modules are still processed.
+	var modulesErrors ModulesErrors
+	if assert.True(t, errors.As(err, &modulesErrors)) && assert.Len(t, modulesErrors, 1) {
+		assert.Equal(t, "example.com/broken", modulesErrors[0].Module.Path)
+		assert.Error(t, errors.Unwrap(modulesErrors[0]))
+	}
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "example.com/root", results[0].Module.Path)
+		assert.Equal(t, map[string]bool{"example.com/root@": true}, results[0].Dependencies)
+		assert.Equal(t, "example.com/sub", results[1].Module.Path)
+		assert.Equal(t, map[string]bool{"example.com/sub@": true}, results[1].Dependencies)
+	}
+}
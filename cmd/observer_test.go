@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type operationsObserver struct {
+	utils.LogObserver
+	events []string
+}
+
+func (observer *operationsObserver) ModuleStarted(event utils.ProgressEvent) {
+	observer.events = append(observer.events, "started "+event.ModuleId)
+}
+
+func (observer *operationsObserver) ModuleFinished(event utils.ProgressEvent) {
+	observer.events = append(observer.events, "finished "+event.ModuleId)
+}
+
+func (observer *operationsObserver) ModuleFailed(event utils.ProgressEvent) {
+	observer.events = append(observer.events, "failed "+event.ModuleId)
+}
+
+func TestRunGoObserver(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	observer := &operationsObserver{}
+	utils.SetObserver(observer)
+	defer utils.SetObserver(nil)
+	assert.NoError(t, runGo([]string{"env", "GOVERSION"}, nil, utils.NewOptions()))
+	assert.Error(t, runGo([]string{"no-such-command"}, nil, utils.NewOptions()))
+	assert.Equal(t, []string{"started env GOVERSION", "finished env GOVERSION", "started no-such-command", "failed no-such-command"}, observer.events)
+}
+
+func TestRunGoObserverOption(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	globalObserver := &operationsObserver{}
+	utils.SetObserver(globalObserver)
+	defer utils.SetObserver(nil)
+	observer := &operationsObserver{}
+	assert.NoError(t, runGo([]string{"env", "GOVERSION"}, nil, utils.NewOptions(utils.WithObserver(observer))))
+	assert.Equal(t, []string{"started env GOVERSION", "finished env GOVERSION"}, observer.events)
+	assert.Empty(t, globalObserver.events)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"archive/zip"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunGoWithProxyUrl(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	tempDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer fileutils.RemoveTempDir(tempDir)
+
+	// Create a module zip in a proxy directory.
+	proxyDir := filepath.Join(tempDir, "proxy")
+	moduleDir := filepath.Join(proxyDir, "example.com", "hello", "@v")
+	assert.NoError(t, os.MkdirAll(moduleDir, 0755))
+	zipFile, err := os.Create(filepath.Join(moduleDir, "v1.0.0.zip"))
+	assert.NoError(t, err)
+	zipWriter := zip.NewWriter(zipFile)
+	for name, content := range map[string]string{"go.mod": "module example.com/hello\n", "hello.go": "package hello\n"} {
+		writer, err := zipWriter.Create("example.com/hello@v1.0.0/" + name)
+		assert.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zipWriter.Close())
+	assert.NoError(t, zipFile.Close())
+
+	server := httptest.NewServer(utils.NewProxyHandler(proxyDir))
+	defer server.Close()
+
+	// Download the module into an empty module cache, without consulting the checksum database.
+	modCache := filepath.Join(tempDir, "modcache")
+	for key, value := range map[string]string{"GOMODCACHE": modCache, "GONOSUMDB": "example.com", "GOFLAGS": "-modcacherw"} {
+		defer os.Setenv(key, os.Getenv(key))
+		assert.NoError(t, os.Setenv(key, value))
+	}
+	assert.NoError(t, RunGoWithProxyUrl([]string{"mod", "download", "example.com/hello@v1.0.0"}, server.URL, true))
+	assert.FileExists(t, filepath.Join(modCache, "cache", "download", "example.com", "hello", "@v", "v1.0.0.zip"))
+	assert.FileExists(t, filepath.Join(modCache, "example.com", "hello@v1.0.0", "hello.go"))
+}
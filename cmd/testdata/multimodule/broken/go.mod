@@ -0,0 +1,7 @@
+module example.com/broken
+
+go 1.16
+
+require example.com/missing v1.0.0
+
+replace example.com/missing => ./missing
@@ -0,0 +1,7 @@
+package a
+
+import "example.com/b"
+
+func Name() string {
+	return "a" + b.Name()
+}
@@ -0,0 +1,5 @@
+package b
+
+func Name() string {
+	return "b"
+}
@@ -0,0 +1,3 @@
+module example.com/notmember
+
+go 1.18
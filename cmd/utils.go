@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -22,12 +23,18 @@ const minGoVersionForMasking = "go1.13"
 // Max go version, which automatically modify go.mod and go.sum when executing build commands.
 const maxGoVersionAutomaticallyModifyMod = "go1.15"
 
+// Minimum go version, which supports the -modfile flag.
+const minGoVersionForModFile = "go1.14"
+
 // Never use this value, use shouldMaskPassword().
 var shouldMask *bool = nil
 
 // Never use this value, use automaticallyModifyMod().
 var autoModify *bool = nil
 
+// Never use this value, use modFileFlagUnsupported().
+var noModFileFlag *bool = nil
+
 func prepareRegExp() error {
 	return prepareGlobalRegExp()
 }
@@ -144,6 +151,11 @@ func automaticallyModifyMod() (bool, error) {
 	return compareSpecificVersionToCurVersion(autoModify, maxGoVersionAutomaticallyModifyMod)
 }
 
+// Since version go1.14 the go command accepts the -modfile flag, which allows using an alternate go.mod file.
+func modFileFlagUnsupported() (bool, error) {
+	return compareSpecificVersionToCurVersion(noModFileFlag, minGoVersionForModFile)
+}
+
 func compareSpecificVersionToCurVersion(result *bool, comparedVersion string) (bool, error) {
 	if result == nil {
 		goVersion, err := getParsedGoVersion()
@@ -167,3 +179,60 @@ func getParsedGoVersion() (*version.Version, error) {
 	splitOutput := strings.Split(output, " ")
 	return version.NewVersion(splitOutput[2]), nil
 }
+
+// Holds the content and the details of a file, so it can be restored after it was modified or created.
+type fileSnapshot struct {
+	path    string
+	exists  bool
+	content []byte
+	stat    os.FileInfo
+}
+
+func takeFileSnapshot(path string) (*fileSnapshot, error) {
+	snapshot := &fileSnapshot{path: path}
+	exists, err := fileutils.IsFileExists(path, false)
+	if err != nil || !exists {
+		return snapshot, err
+	}
+	snapshot.exists = true
+	snapshot.content, snapshot.stat, err = GetFileDetails(path)
+	return snapshot, err
+}
+
+// Restores the file to its state when the snapshot was taken, if it was changed.
+// The content is written to a temp file in the same directory, which then replaces the original file,
+// so the file is never left partially written. The file mode and modification time are preserved.
+func (snapshot *fileSnapshot) restore(logger log.Log) error {
+	if !snapshot.exists {
+		exists, err := fileutils.IsFileExists(snapshot.path, false)
+		if err != nil || !exists {
+			return err
+		}
+		logger.Debug("Removing file:", snapshot.path)
+		return errorutils.CheckError(os.Remove(snapshot.path))
+	}
+	currentContent, err := ioutil.ReadFile(snapshot.path)
+	if err == nil && bytes.Equal(currentContent, snapshot.content) {
+		return nil
+	}
+	logger.Debug("Restoring file:", snapshot.path)
+	tempFile, err := ioutil.TempFile(filepath.Dir(snapshot.path), "."+filepath.Base(snapshot.path)+".*")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer os.Remove(tempFile.Name())
+	_, err = tempFile.Write(snapshot.content)
+	if e := tempFile.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = os.Chmod(tempFile.Name(), snapshot.stat.Mode()); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = os.Chtimes(tempFile.Name(), snapshot.stat.ModTime(), snapshot.stat.ModTime()); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.Rename(tempFile.Name(), snapshot.path))
+}
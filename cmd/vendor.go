@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	vendorDirName      = "vendor"
+	vendorModulesFile  = "modules.txt"
+	vendorModulePrefix = "# "
+	vendorMarkerPrefix = "## "
+	// The go directive version from which the go command uses the vendor directory by default.
+	vendorModeMinGoVersion = "v1.14"
+)
+
+// Represents a module listed in the vendor/modules.txt file.
+type VendoredModule struct {
+	Path    string
+	Version string
+	// True if the module is explicitly required in the main go.mod file.
+	Explicit bool
+	// The go version declared in the module's go.mod file, if known.
+	GoVersion string
+	// Not nil if the module is replaced in the main go.mod file.
+	Replace *ModuleReplacement
+	// The packages of this module which are vendored.
+	Packages []string
+}
+
+// Represents the target of a replace directive.
+// A replacement to a local directory has an empty version.
+type ModuleReplacement struct {
+	Path    string
+	Version string
+}
+
+// Returns true if the replacement points to a local directory rather than to a module version.
+func (replacement *ModuleReplacement) IsLocal() bool {
+	return replacement.Version == ""
+}
+
+// Returns the module path and version from which the vendored sources were taken.
+func (module *VendoredModule) Source() (path, version string) {
+	if module.Replace != nil {
+		return module.Replace.Path, module.Replace.Version
+	}
+	return module.Path, module.Version
+}
+
+// Returns the module id in the format used by GetDependenciesList: <module path>@<version>
+func (module *VendoredModule) GetId() string {
+	return module.Path + "@" + module.Version
+}
+
+// Returns the path of the vendor directory of the project.
+func GetVendorDir(projectDir string) string {
+	return filepath.Join(projectDir, vendorDirName)
+}
+
+// Returns true if the project at projectDir has a vendor/modules.txt file.
+func IsVendored(projectDir string) (bool, error) {
+	return fileutils.IsFileExists(filepath.Join(GetVendorDir(projectDir), vendorModulesFile), false)
+}
+
+// Returns true if the go command builds the project at projectDir in vendor mode:
+// If -mod=vendor is set in GOFLAGS, or if GOFLAGS doesn't set another -mod flag, the project has a vendor/modules.txt file,
+// and the go directive of its go.mod file is 1.14 or higher, since vendor mode is the default only from go 1.14.
+func IsVendorMode(projectDir string) (bool, error) {
+	for _, flag := range strings.Fields(os.Getenv(goFlagsEnv)) {
+		if strings.HasPrefix(flag, "-mod=") {
+			return flag == "-mod=vendor", nil
+		}
+	}
+	var err error
+	if projectDir == "" {
+		projectDir, err = GetProjectRoot()
+		if err != nil {
+			return false, err
+		}
+	}
+	vendored, err := IsVendored(projectDir)
+	if err != nil || !vendored {
+		return false, err
+	}
+	goVersion, err := getGoDirective(filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return false, err
+	}
+	return goVersion != "" && semver.Compare("v"+goVersion, vendorModeMinGoVersion) >= 0, nil
+}
+
+// Returns the version of the go directive of the go.mod file, or an empty string if the file or its go directive is missing.
+func getGoDirective(goModPath string) (string, error) {
+	exists, err := fileutils.IsFileExists(goModPath, false)
+	if err != nil || !exists {
+		return "", err
+	}
+	content, err := ioutil.ReadFile(goModPath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	goMod, err := modfile.ParseLax(goModPath, content, nil)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	if goMod.Go == nil {
+		return "", nil
+	}
+	return goMod.Go.Version, nil
+}
+
+// Reads the vendor/modules.txt file of the project and returns the vendored modules.
+// Modules which are only replaced, without being vendored, are not returned.
+func GetVendoredModules(projectDir string) ([]VendoredModule, error) {
+	var err error
+	if projectDir == "" {
+		projectDir, err = GetProjectRoot()
+		if err != nil {
+			return nil, err
+		}
+	}
+	modulesTxtPath := filepath.Join(GetVendorDir(projectDir), vendorModulesFile)
+	log.Debug("Reading vendored modules from", modulesTxtPath)
+	content, err := ioutil.ReadFile(modulesTxtPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return parseVendorModulesTxt(string(content))
+}
+
+// Runs GetVendoredModules and returns a map of the vendored modules, in the same format as GetDependenciesList.
+func GetVendoredDependenciesList(projectDir string) (map[string]bool, error) {
+	modules, err := GetVendoredModules(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	mapOfDeps := map[string]bool{}
+	for _, module := range modules {
+		mapOfDeps[module.GetId()] = true
+	}
+	return mapOfDeps, nil
+}
+
+// Parses the content of a vendor/modules.txt file.
+// The expected syntax is:
+// # github.com/name v1.2.3 [=> github.com/other v1.2.4 | => ../local/path]
+// ## explicit; go 1.17
+// github.com/name/package
+func parseVendorModulesTxt(content string) ([]VendoredModule, error) {
+	var modules []VendoredModule
+	var current *VendoredModule
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, vendorMarkerPrefix):
+			if current == nil {
+				return nil, vendorSyntaxError(i, line)
+			}
+			parseVendorMarkers(strings.TrimPrefix(line, vendorMarkerPrefix), current)
+		case strings.HasPrefix(line, vendorModulePrefix):
+			module, err := parseVendorModuleLine(strings.TrimPrefix(line, vendorModulePrefix))
+			if err != nil {
+				return nil, vendorSyntaxError(i, line)
+			}
+			modules = append(modules, module)
+			current = &modules[len(modules)-1]
+		default:
+			if current == nil {
+				return nil, vendorSyntaxError(i, line)
+			}
+			current.Packages = append(current.Packages, line)
+		}
+	}
+	// Modules without a version are replacement-only entries ('# path => target'), which have no vendored sources.
+	var vendored []VendoredModule
+	for _, module := range modules {
+		if module.Version != "" {
+			vendored = append(vendored, module)
+		}
+	}
+	return vendored, nil
+}
+
+func parseVendorModuleLine(line string) (VendoredModule, error) {
+	module := VendoredModule{}
+	parts := strings.SplitN(line, "=>", 2)
+	fields := strings.Fields(parts[0])
+	if len(fields) < 1 || len(fields) > 2 {
+		return module, errors.New("unexpected module line")
+	}
+	module.Path = fields[0]
+	if len(fields) == 2 {
+		module.Version = fields[1]
+	}
+	if len(parts) == 2 {
+		target := strings.Fields(parts[1])
+		if len(target) < 1 || len(target) > 2 {
+			return module, errors.New("unexpected replacement")
+		}
+		module.Replace = &ModuleReplacement{Path: target[0]}
+		if len(target) == 2 {
+			module.Replace.Version = target[1]
+		}
+	}
+	return module, nil
+}
+
+// Parses the '## explicit; go 1.17' markers line.
+func parseVendorMarkers(line string, module *VendoredModule) {
+	for _, marker := range strings.Split(line, ";") {
+		marker = strings.TrimSpace(marker)
+		switch {
+		case marker == "explicit":
+			module.Explicit = true
+		case strings.HasPrefix(marker, "go "):
+			module.GoVersion = strings.TrimSpace(strings.TrimPrefix(marker, "go "))
+		}
+	}
+}
+
+func vendorSyntaxError(lineIndex int, line string) error {
+	return errorutils.CheckError(errors.New(fmt.Sprintf("Unexpected syntax in %s line %d: '%s'", vendorModulesFile, lineIndex+1, line)))
+}
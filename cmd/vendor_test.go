@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVendorModulesTxt(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	content, err := ioutil.ReadFile(filepath.Join("testdata", "vendor", "modules.txt"))
+	assert.NoError(t, err)
+	actual, err := parseVendorModulesTxt(string(content))
+	assert.NoError(t, err)
+	expected := []VendoredModule{
+		{Path: "github.com/jfrog/gofrog", Version: "v1.1.0", Explicit: true, GoVersion: "1.14", Packages: []string{"github.com/jfrog/gofrog/io", "github.com/jfrog/gofrog/version"}},
+		{Path: "golang.org/x/text", Version: "v0.3.3", Packages: []string{"golang.org/x/text/language"}},
+		{Path: "rsc.io/quote", Version: "v1.5.2", Explicit: true, Replace: &ModuleReplacement{Path: "github.com/forked/quote", Version: "v1.5.3"}, Packages: []string{"rsc.io/quote"}},
+		{Path: "example.com/local", Version: "v0.0.0-00010101000000-000000000000", Explicit: true, Replace: &ModuleReplacement{Path: "../local"}, Packages: []string{"example.com/local"}},
+	}
+	assert.Equal(t, expected, actual)
+	assert.True(t, actual[3].Replace.IsLocal())
+	path, version := actual[2].Source()
+	assert.Equal(t, "github.com/forked/quote", path)
+	assert.Equal(t, "v1.5.3", version)
+}
+
+func TestParseVendorModulesTxtInvalid(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	_, err := parseVendorModulesTxt("## explicit\n")
+	assert.Error(t, err)
+	_, err = parseVendorModulesTxt("rsc.io/quote\n")
+	assert.Error(t, err)
+	_, err = parseVendorModulesTxt("# rsc.io/quote v1.5.2 extra\n")
+	assert.Error(t, err)
+}
+
+func TestGetVendoredDependenciesList(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	actual, err := GetVendoredDependenciesList("testdata")
+	assert.NoError(t, err)
+	expected := map[string]bool{
+		"github.com/jfrog/gofrog@v1.1.0":                       true,
+		"golang.org/x/text@v0.3.3":                             true,
+		"rsc.io/quote@v1.5.2":                                  true,
+		"example.com/local@v0.0.0-00010101000000-000000000000": true,
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestIsVendorMode(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	defer os.Setenv(goFlagsEnv, os.Getenv(goFlagsEnv))
+	assert.NoError(t, os.Setenv(goFlagsEnv, ""))
+	projectDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer fileutils.RemoveTempDir(projectDir)
+	assert.NoError(t, os.MkdirAll(GetVendorDir(projectDir), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(GetVendorDir(projectDir), vendorModulesFile), []byte("# rsc.io/quote v1.5.2\n"), 0644))
+
+	// Vendor mode is the default only from go 1.14.
+	for goMod, expected := range map[string]bool{
+		"module example.com/project\n":            false,
+		"module example.com/project\n\ngo 1.13\n": false,
+		"module example.com/project\n\ngo 1.14\n": true,
+		"module example.com/project\n\ngo 1.21\n": true,
+	} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(projectDir, "go.mod"), []byte(goMod), 0644))
+		vendorMode, err := IsVendorMode(projectDir)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, vendorMode, goMod)
+	}
+
+	// The -mod flag takes precedence.
+	assert.NoError(t, os.Setenv(goFlagsEnv, "-mod=vendor"))
+	vendorMode, err := IsVendorMode(projectDir)
+	assert.NoError(t, err)
+	assert.True(t, vendorMode)
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	goWorkFileName = "go.work"
+	goWorkEnv      = "GOWORK"
+	goFlagsEnv     = "GOFLAGS"
+)
+
+// Represents a Go workspace, defined by a go.work file.
+type Workspace struct {
+	// The absolute path to the go.work file.
+	GoWorkPath string
+	// The go version declared in the go.work file.
+	GoVersion string
+	// The modules listed in the 'use' directives of the go.work file.
+	Members []WorkspaceMember
+}
+
+// Represents a module which is used by a workspace.
+type WorkspaceMember struct {
+	// The module path, as declared in the go.mod file of the member.
+	Path string
+	// The absolute path to the directory of the member.
+	Dir string
+}
+
+// Returns the root dir of the workspace, where the go.work file is located.
+func (workspace *Workspace) Dir() string {
+	return filepath.Dir(workspace.GoWorkPath)
+}
+
+// Returns the member which its path is modulePath, or nil if there is no such member.
+func (workspace *Workspace) GetMember(modulePath string) *WorkspaceMember {
+	for i := range workspace.Members {
+		if workspace.Members[i].Path == modulePath {
+			return &workspace.Members[i]
+		}
+	}
+	return nil
+}
+
+// Returns the path to the go.work file which applies to dir, or an empty string if dir is not part of a workspace.
+// Like the go command, the GOWORK environment variable is used if set. Otherwise, dir and its parent directories are searched.
+func FindGoWork(dir string) (string, error) {
+	goWork := os.Getenv(goWorkEnv)
+	switch {
+	case goWork == "off":
+		return "", nil
+	case goWork != "":
+		goWorkPath, err := filepath.Abs(goWork)
+		return goWorkPath, errorutils.CheckError(err)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	for {
+		goWorkPath := filepath.Join(dir, goWorkFileName)
+		exists, err := fileutils.IsFileExists(goWorkPath, false)
+		if err != nil || exists {
+			if exists {
+				log.Debug("Found go.work file:", goWorkPath)
+			}
+			return goWorkPath, err
+		}
+		parent := filepath.Dir(dir)
+		// If this the OS root, we can stop.
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Returns the workspace which dir is part of, or nil if dir is not part of a workspace.
+func GetWorkspace(dir string) (*Workspace, error) {
+	var err error
+	if dir == "" {
+		dir, err = GetProjectRoot()
+		if err != nil {
+			return nil, err
+		}
+	}
+	goWorkPath, err := FindGoWork(dir)
+	if err != nil || goWorkPath == "" {
+		return nil, err
+	}
+	return ReadWorkspace(goWorkPath)
+}
+
+// Parses the go.work file and the go.mod files of its members.
+func ReadWorkspace(goWorkPath string) (*Workspace, error) {
+	goWorkPath, err := filepath.Abs(goWorkPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	content, err := ioutil.ReadFile(goWorkPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	workFile, err := modfile.ParseWork(goWorkPath, content, nil)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	workspace := &Workspace{GoWorkPath: goWorkPath}
+	if workFile.Go != nil {
+		workspace.GoVersion = workFile.Go.Version
+	}
+	for _, use := range workFile.Use {
+		memberDir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(memberDir) {
+			memberDir = filepath.Join(workspace.Dir(), memberDir)
+		}
+		goMod, err := utils.ReadGoMod(filepath.Join(memberDir, "go.mod"))
+		if err != nil {
+			return nil, err
+		}
+		modulePath := goMod.Module
+		if modulePath == "" {
+			return nil, errorutils.CheckError(errors.New(fmt.Sprintf("Could not find the module path in %s", filepath.Join(memberDir, "go.mod"))))
+		}
+		workspace.Members = append(workspace.Members, WorkspaceMember{Path: modulePath, Dir: memberDir})
+	}
+	return workspace, nil
+}
+
+// Runs go list in the workspace root and returns a map of the combined build list of all the workspace members.
+// The members themselves are included, with an empty version.
+func GetWorkspaceDependenciesList(workspace *Workspace, options ...utils.Option) (map[string]bool, error) {
+	opts := utils.NewOptions(options...)
+	output, err := runWorkspaceDependenciesCmd(workspace.Dir(), workspace.GoWorkPath, []string{"list", "-f", "{{with .Module}}{{.Path}}@{{.Version}}{{end}}", "all"}, opts)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := listToMap(output)
+	if err = opts.RunDependenciesChecks(dependencies); err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// Runs go list in the member directory and returns a map of the dependencies of the member packages.
+// The versions are selected according to the combined build list of the workspace.
+func GetWorkspaceMemberDependenciesList(workspace *Workspace, member WorkspaceMember, options ...utils.Option) (map[string]bool, error) {
+	opts := utils.NewOptions(options...)
+	output, err := runWorkspaceDependenciesCmd(member.Dir, workspace.GoWorkPath, []string{"list", "-deps", "-test", "-f", "{{with .Module}}{{.Path}}@{{.Version}}{{end}}", "./..."}, opts)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := listToMap(output)
+	if err = opts.RunDependenciesChecks(dependencies); err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// Runs a dependencies command in workspace mode.
+// The go command doesn't modify the go.mod and go.sum files in workspace mode, and doesn't allow the -mod=mod flag.
+// Therefore, the flag is removed from the command and from GOFLAGS, and the files don't need to be restored.
+func runWorkspaceDependenciesCmd(dir, goWorkPath string, commandArgs []string, opts *utils.Options) (string, error) {
+	var args []string
+	for _, arg := range commandArgs {
+		if arg != "-mod=mod" {
+			args = append(args, arg)
+		}
+	}
+	opts.GetLogger().Debug(fmt.Sprintf("Running 'go %s' in %s using the workspace %s", strings.Join(args, " "), dir, goWorkPath))
+	env := map[string]string{goWorkEnv: goWorkPath}
+	if goFlags := os.Getenv(goFlagsEnv); goFlags != "" {
+		env[goFlagsEnv] = removeModFlag(goFlags)
+	}
+	return runGoCmdInDir(dir, args, env, opts)
+}
+
+func removeModFlag(goFlags string) string {
+	var flags []string
+	for _, flag := range strings.Fields(goFlags) {
+		if flag != "-mod=mod" {
+			flags = append(flags, flag)
+		}
+	}
+	return strings.Join(flags, " ")
+}
+
+// Returns the module path of the workspace member located at dir, or an empty string if dir is not a member of the workspace.
+func getWorkspaceMemberNameByDir(workspace *Workspace, dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	for _, member := range workspace.Members {
+		if member.Dir == dir {
+			return member.Path, nil
+		}
+	}
+	return "", nil
+}
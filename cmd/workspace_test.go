@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// A dependencies check which records the dependencies it checked, and returns err.
+type recordingCheck struct {
+	dependencies map[string]bool
+	err          error
+}
+
+func (check *recordingCheck) CheckDependencies(dependencies map[string]bool) error {
+	check.dependencies = dependencies
+	return check.err
+}
+
+func TestGetWorkspace(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	workspaceDir, err := filepath.Abs(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+
+	// A member of the workspace.
+	workspace, err := GetWorkspace(filepath.Join(workspaceDir, "a"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, workspace) {
+		assert.Equal(t, filepath.Join(workspaceDir, "go.work"), workspace.GoWorkPath)
+		assert.Equal(t, workspaceDir, workspace.Dir())
+		assert.Equal(t, "1.18", workspace.GoVersion)
+		expected := []WorkspaceMember{
+			{Path: "example.com/a", Dir: filepath.Join(workspaceDir, "a")},
+			{Path: "example.com/b", Dir: filepath.Join(workspaceDir, "b")},
+		}
+		assert.Equal(t, expected, workspace.Members)
+		assert.Equal(t, &expected[1], workspace.GetMember("example.com/b"))
+		assert.Nil(t, workspace.GetMember("example.com/c"))
+	}
+
+	// A directory outside of any workspace.
+	workspace, err = GetWorkspace(filepath.Join("testdata", "project"))
+	assert.NoError(t, err)
+	assert.Nil(t, workspace)
+}
+
+func TestFindGoWorkWithEnv(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	defer os.Unsetenv(goWorkEnv)
+	assert.NoError(t, os.Setenv(goWorkEnv, "off"))
+	goWorkPath, err := FindGoWork(filepath.Join("testdata", "workspace", "a"))
+	assert.NoError(t, err)
+	assert.Empty(t, goWorkPath)
+
+	expected, err := filepath.Abs(filepath.Join("testdata", "workspace", "go.work"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.Setenv(goWorkEnv, expected))
+	goWorkPath, err = FindGoWork(filepath.Join("testdata", "project"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, goWorkPath)
+}
+
+func TestGetProjectRootInWorkspace(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer os.Chdir(wd)
+	workspaceDir, err := filepath.Abs(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+
+	// A directory without go.mod inside the workspace.
+	assert.NoError(t, os.Chdir(filepath.Join(workspaceDir, "tools")))
+	root, err := GetProjectRoot()
+	assert.NoError(t, err)
+	assert.Equal(t, workspaceDir, root)
+
+	// The go.mod of a member is found before the go.work file.
+	assert.NoError(t, os.Chdir(filepath.Join(workspaceDir, "a")))
+	root, err = GetProjectRoot()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workspaceDir, "a"), root)
+}
+
+func TestGetWorkspaceDependenciesList(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	goVersion, err := getParsedGoVersion()
+	assert.NoError(t, err)
+	if !goVersion.AtLeast("go1.18") {
+		t.Skip("Go workspaces are supported since go1.18")
+	}
+	workspace, err := GetWorkspace(filepath.Join("testdata", "workspace"))
+	assert.NoError(t, err)
+	if !assert.NotNil(t, workspace) {
+		return
+	}
+
+	actual, err := GetWorkspaceDependenciesList(workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com/a@": true, "example.com/b@": true}, actual)
+
+	actual, err = GetWorkspaceMemberDependenciesList(workspace, workspace.Members[1])
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com/b@": true}, actual)
+
+	// The dependencies of the workspace root are the combined build list.
+	actual, err = GetDependenciesList(workspace.Dir())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com/a@": true, "example.com/b@": true}, actual)
+
+	actual, err = GetBuildList(workspace.Dir())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com/a@": true, "example.com/b@": true}, actual)
+
+	// The dependencies checks run on the listed dependencies.
+	check := &recordingCheck{err: errors.New("denied")}
+	_, err = GetDependenciesList(workspace.Dir(), utils.WithDependenciesCheck(check))
+	assert.EqualError(t, err, "denied")
+	assert.Equal(t, actual, check.dependencies)
+
+	name, err := GetModuleNameByDir(workspace.Members[0].Dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/a", name)
+
+	// A module inside the workspace tree, which isn't used by the workspace.
+	name, err = GetModuleNameByDir(filepath.Join(workspace.Dir(), "notmember"))
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/notmember", name)
+}
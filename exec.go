@@ -2,9 +2,34 @@ package gocmd
 
 import (
 	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/executers"
+	"github.com/jfrog/gocmd/executers/utils"
 	"github.com/jfrog/jfrog-client-go/auth"
 )
 
-func Run(goArg []string, server auth.ServiceDetails, repo string, noFallback bool) error {
-	return cmd.RunGo(goArg, server, repo, noFallback)
+// Runs the go command with the Go repository in Artifactory as the GOPROXY. See cmd.RunGo.
+// With utils.WithPhasedDownloads, the dependencies of the project in the current directory are downloaded in phases
+// before the go command runs. See executers.DownloadDependenciesWithFallback.
+func Run(goArg []string, server auth.ServiceDetails, repo string, noFallback bool, options ...utils.Option) error {
+	if utils.NewOptions(options...).PhasedDownloads {
+		if err := downloadInPhases(server, repo, noFallback, options...); err != nil {
+			return err
+		}
+	}
+	return cmd.RunGo(goArg, server, repo, noFallback, options...)
+}
+
+func downloadInPhases(server auth.ServiceDetails, repo string, noFallback bool, options ...utils.Option) error {
+	utils.SetGoProxyWithApi(repo, server, noFallback)
+	// Only the go.mod files are downloaded for listing the modules, so their zips are downloaded in phases.
+	dependencies, err := cmd.GetBuildList("", options...)
+	if err != nil {
+		return err
+	}
+	cachePath, err := cmd.GetCachePath()
+	if err != nil {
+		return err
+	}
+	_, err = executers.DownloadDependenciesWithFallback(dependencies, cachePath, server, repo, noFallback, options...)
+	return err
 }
@@ -0,0 +1,308 @@
+package executers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/tests/artifactorytest"
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+const testModContent = "module rsc.io/quote\n\nrequire rsc.io/sampler v1.3.0\n"
+
+func createArtifactoryTestServer(t *testing.T) *artifactorytest.Server {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	server, err := artifactorytest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, server.AddRepo("go-local"))
+	return server
+}
+
+// Creates a cache directory which includes rsc.io/quote@v1.5.2.
+func createTestCache(t *testing.T) string {
+	cachePath, err := ioutil.TempDir("", "artifactoryTestCache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versionDir := filepath.Join(cachePath, "rsc.io", "quote", "@v")
+	assert.NoError(t, os.MkdirAll(versionDir, 0755))
+	assert.NoError(t, fileutils.CopyFile(versionDir, filepath.Join("..", "testdata", "zip", "rsc.io", "quote", "@v", "v1.5.2.zip")))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(versionDir, "v1.5.2.mod"), []byte(testModContent), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(versionDir, "v1.5.2.info"), []byte(`{"Version":"v1.5.2","Time":"2018-02-14T15:44:20Z"}`), 0644))
+	return cachePath
+}
+
+func TestPublish(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+
+	dep, err := createDependency(cachePath, "rsc.io/quote", "v1.5.2", utils.NewOptions())
+	assert.NoError(t, err)
+	assert.NoError(t, dep.Publish("", "go-local", servicesManager))
+
+	uploads := server.Uploads()
+	if assert.Len(t, uploads, 3) {
+		var exts []string
+		for _, upload := range uploads {
+			assert.Equal(t, "go-local", upload.Repo)
+			assert.Equal(t, "rsc.io/quote", upload.Module)
+			assert.Equal(t, "v1.5.2", upload.Version)
+			assert.Equal(t, "v1.5.2", upload.Properties["go.version"])
+			exts = append(exts, upload.Ext)
+			if upload.Ext == ".mod" {
+				assert.Equal(t, testModContent, string(upload.Content))
+			}
+		}
+		assert.ElementsMatch(t, []string{".zip", ".mod", ".info"}, exts)
+	}
+
+	// Publishing to a repository which doesn't exist should fail.
+	assert.Error(t, dep.Publish("", "go-missing", servicesManager))
+
+	// Wrong credentials are reported as a typed error.
+	server.SetCredentials(artifactorytest.DefaultUser, "wrong")
+	err = dep.Publish("", "go-local", servicesManager)
+	var authErr *utils.AuthenticationError
+	if assert.True(t, errors.As(err, &authErr), err) {
+		assert.Equal(t, "rsc.io/quote", authErr.Module)
+		assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+	}
+}
+
+func TestPublishError(t *testing.T) {
+	dep := &Package{id: "github.com/!azure/go:v1.0.0", version: "v1.0.0"}
+	var checksumErr *utils.ChecksumMismatchError
+	if assert.True(t, errors.As(dep.publishError(errors.New("Server response: 409 Conflict\nChecksum mismatch"), "url"), &checksumErr)) {
+		assert.Equal(t, "github.com/Azure/go", checksumErr.Module)
+	}
+	otherErr := errors.New("Server response: 500 Internal Server Error")
+	assert.Equal(t, otherErr, dep.publishError(otherErr, "url"))
+}
+
+func TestPreviousTriesError(t *testing.T) {
+	artifactoryErr := &utils.ModuleNotFoundError{Module: "rsc.io/quote", Version: "v1.5.2"}
+	vcsErr := errors.New("exit status 128")
+	tries := &previousTries{}
+	tries.setTriedFrom(true)
+	assert.Equal(t, artifactoryErr, tries.newError("rsc.io/quote", "v1.5.2", artifactoryErr, nil))
+	tries.setTriedFrom(false)
+	var bothErr *utils.BothSourcesError
+	if assert.True(t, errors.As(tries.newError("rsc.io/quote", "v1.5.2", artifactoryErr, vcsErr), &bothErr)) {
+		assert.Equal(t, artifactoryErr, bothErr.ArtifactoryErr)
+		assert.Equal(t, &utils.VcsFallbackError{Module: "rsc.io/quote", Version: "v1.5.2", Err: vcsErr}, bothErr.VcsErr)
+	}
+	var vcsFallbackErr *utils.VcsFallbackError
+	assert.True(t, errors.As((&previousTries{triedFromVCS: true}).newError("rsc.io/quote", "v1.5.2", nil, vcsErr), &vcsFallbackErr))
+}
+
+func TestPerformHeadRequest(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	assert.NoError(t, server.AddModule("go-local", "rsc.io/quote", "v1.5.2", map[string][]byte{".mod": []byte(testModContent)}))
+	client, err := httpclient.ClientBuilder().Build()
+	assert.NoError(t, err)
+
+	resp, err := performHeadRequest(server.ServiceDetails(), client, "go-local", "rsc.io/quote", "v1.5.2", utils.GetGlobalLogger())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = performHeadRequest(server.ServiceDetails(), client, "go-local", "rsc.io/quote", "v1.5.3", utils.GetGlobalLogger())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	shouldDownload, err := shouldDownloadFromArtifactory("rsc.io/quote", "v1.5.2", "go-local", server.ServiceDetails(), client, utils.GetGlobalLogger())
+	assert.NoError(t, err)
+	assert.True(t, shouldDownload)
+
+	// Wrong credentials should be rejected.
+	details := server.ServiceDetails()
+	details.SetPassword("wrong")
+	resp, err = performHeadRequest(details, client, "go-local", "rsc.io/quote", "v1.5.2", utils.GetGlobalLogger())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestDownloadModFileFromArtifactoryToLocalCache(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	assert.NoError(t, server.AddModule("go-local", "rsc.io/quote", "v1.5.2", map[string][]byte{".mod": []byte(testModContent)}))
+	client, err := httpclient.ClientBuilder().Build()
+	assert.NoError(t, err)
+	cachePath, err := ioutil.TempDir("", "artifactoryTestCache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cachePath)
+
+	// The mod file is downloaded only if the module directory exists in the cache.
+	modPath, err := downloadModFileFromArtifactoryToLocalCache(cachePath, "go-local", "rsc.io/quote", "v1.5.2", server.ServiceDetails(), client, utils.NewOptions())
+	assert.NoError(t, err)
+	assert.Empty(t, modPath)
+	versionDir := filepath.Join(cachePath, "rsc.io", "quote", "@v")
+	assert.NoError(t, os.MkdirAll(versionDir, 0755))
+	modPath, err = downloadModFileFromArtifactoryToLocalCache(cachePath, "go-local", "rsc.io/quote", "v1.5.2", server.ServiceDetails(), client, utils.NewOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(versionDir, "v1.5.2.mod"), modPath)
+	content, err := ioutil.ReadFile(modPath)
+	assert.NoError(t, err)
+	assert.Equal(t, testModContent, string(content))
+
+	// A missing version is reported as a typed error.
+	_, err = downloadModFileFromArtifactoryToLocalCache(cachePath, "go-local", "rsc.io/quote", "v1.5.3", server.ServiceDetails(), client, utils.NewOptions())
+	var notFoundErr *utils.ModuleNotFoundError
+	if assert.True(t, errors.As(err, &notFoundErr)) {
+		assert.Equal(t, "rsc.io/quote", notFoundErr.Module)
+		assert.Equal(t, "v1.5.3", notFoundErr.Version)
+	}
+
+	// So are wrong credentials.
+	details := server.ServiceDetails()
+	details.SetPassword("wrong")
+	_, err = downloadModFileFromArtifactoryToLocalCache(cachePath, "go-local", "rsc.io/quote", "v1.5.2", details, client, utils.NewOptions())
+	var authErr *utils.AuthenticationError
+	if assert.True(t, errors.As(err, &authErr)) {
+		assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+	}
+}
+
+func TestPopulateModAndPublishReport(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+	dep, err := createDependency(cachePath, "rsc.io/quote", "v1.5.2", utils.NewOptions())
+	assert.NoError(t, err)
+
+	dependenciesCache := &cache.DependenciesCache{}
+	dependenciesCache.IncrementTotal(1)
+	assert.NoError(t, dep.PopulateModAndPublish("go-local", dependenciesCache, servicesManager))
+	dependenciesCache.GetMap()[dep.GetId()] = true
+	assert.NoError(t, dep.PopulateModAndPublish("go-local", dependenciesCache, servicesManager))
+
+	report := dependenciesCache.GetReport()
+	assert.Equal(t, 1, report.Published)
+	assert.Equal(t, 1, report.Skipped)
+	if assert.Len(t, report.Modules, 2) {
+		var size int64
+		for _, upload := range server.Uploads() {
+			size += int64(len(upload.Content))
+		}
+		assert.Equal(t, size, report.Modules[0].Bytes)
+		assert.Equal(t, server.URL+"/api/go/go-local/rsc.io/quote/@v/v1.5.2", report.Modules[0].TargetUrl)
+		assert.Equal(t, cache.Skipped, report.Modules[1].Outcome)
+	}
+
+	failedCache := &cache.DependenciesCache{}
+	assert.Error(t, dep.PopulateModAndPublish("go-missing", failedCache, servicesManager))
+	if assert.Len(t, failedCache.GetRecords(), 1) {
+		assert.Equal(t, cache.Failed, failedCache.GetRecords()[0].Outcome)
+		assert.NotEmpty(t, failedCache.GetRecords()[0].Error)
+	}
+}
+
+type recordingObserver struct {
+	events []string
+	// The bytes of the last ModuleFinished event.
+	bytes int64
+	// The bytes of the BytesTransferred events.
+	transferred []int64
+}
+
+func (observer *recordingObserver) record(name string, event utils.ProgressEvent) {
+	observer.events = append(observer.events, fmt.Sprintf("%s %s %s", name, event.Operation, event.ModuleId))
+}
+
+func (observer *recordingObserver) ModuleStarted(event utils.ProgressEvent) {
+	observer.record("started", event)
+}
+
+func (observer *recordingObserver) BytesTransferred(event utils.ProgressEvent) {
+	observer.transferred = append(observer.transferred, event.Bytes)
+}
+
+func (observer *recordingObserver) ModuleFinished(event utils.ProgressEvent) {
+	observer.record("finished", event)
+	observer.bytes = event.Bytes
+}
+
+func (observer *recordingObserver) ModuleFailed(event utils.ProgressEvent) {
+	observer.record("failed", event)
+}
+
+func (observer *recordingObserver) ModuleRetried(event utils.ProgressEvent) {
+	observer.record("retried", event)
+}
+
+func TestPublishObserver(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+	dep, err := createDependency(cachePath, "rsc.io/quote", "v1.5.2", utils.NewOptions())
+	assert.NoError(t, err)
+
+	observer := &recordingObserver{}
+	dep.SetObserver(observer)
+	assert.NoError(t, dep.Publish("1/2", "go-local", servicesManager))
+	assert.Equal(t, dep.getFilesSize(), observer.bytes)
+	assert.Equal(t, []int64{dep.getFilesSize()}, observer.transferred)
+	assert.Error(t, dep.Publish("2/2", "go-missing", servicesManager))
+	assert.Equal(t, []string{
+		"started publish rsc.io/quote:v1.5.2",
+		"finished publish rsc.io/quote:v1.5.2",
+		"started publish rsc.io/quote:v1.5.2",
+		"failed publish rsc.io/quote:v1.5.2",
+	}, observer.events)
+
+	// The mod file download is reported to the global observer.
+	utils.SetObserver(observer)
+	defer utils.SetObserver(nil)
+	observer.events, observer.transferred = nil, nil
+	client, err := httpclient.ClientBuilder().Build()
+	assert.NoError(t, err)
+	modPath, err := downloadModFileFromArtifactoryToLocalCache(cachePath, "go-local", "rsc.io/quote", "v1.5.2", server.ServiceDetails(), client, utils.NewOptions())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, modPath)
+	assert.Equal(t, "started download rsc.io/quote:v1.5.2", observer.events[0])
+	assert.Equal(t, "finished download rsc.io/quote:v1.5.2", observer.events[len(observer.events)-1])
+	modContent, err := ioutil.ReadFile(modPath)
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, observer.transferred) {
+		assert.Equal(t, int64(len(modContent)), observer.transferred[len(observer.transferred)-1])
+	}
+	assert.Equal(t, int64(len(modContent)), observer.bytes)
+}
+
+func TestPublishLogger(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+	dep, err := createDependency(cachePath, "rsc.io/quote", "v1.5.2", utils.NewOptions())
+	assert.NoError(t, err)
+
+	buffer := &bytes.Buffer{}
+	dep.SetLogger(utils.WithLogFields(log.NewLogger(log.INFO, buffer), utils.LogFields{"runId": "42"}))
+	assert.NoError(t, dep.Publish("", "go-local", servicesManager))
+	assert.Contains(t, buffer.String(), "[module=rsc.io/quote:v1.5.2 runId=42] Publishing: rsc.io/quote:v1.5.2 to go-local")
+}
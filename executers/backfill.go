@@ -0,0 +1,123 @@
+package executers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/params"
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Returned by BackfillArtifactory when some of the dependencies couldn't be checked or published.
+type BackfillError struct {
+	// The errors of the dependencies which couldn't be checked, by the dependency id.
+	Errors map[string]error
+	// The errors of the missing dependencies which couldn't be published to some of the deployers, or nil.
+	PublishErr *PartialPublishError
+}
+
+func (backfillError *BackfillError) Error() string {
+	var messages []string
+	for id, err := range backfillError.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", id, err.Error()))
+	}
+	sort.Strings(messages)
+	if backfillError.PublishErr != nil {
+		messages = append(messages, backfillError.PublishErr.Error())
+	}
+	return fmt.Sprintf("Backfilling Artifactory failed:\n%s", strings.Join(messages, "\n"))
+}
+
+func (backfillError *BackfillError) Unwrap() error {
+	if backfillError.PublishErr == nil {
+		return nil
+	}
+	return backfillError.PublishErr
+}
+
+// Publishes the dependencies which are missing from Artifactory, such as modules which were downloaded directly from VCS, from the local cache.
+// The dependencies are in the format returned by cmd.GetDependenciesList. Dependencies which don't have a zip in the cache are ignored.
+// A dependency is missing if a HEAD request for its go.mod file in the resolver repository doesn't find it.
+// If the resolver is not set, the primary deployer repository is checked instead. The missing dependencies are published to all the deployers, see PublishToDeployers.
+// The published dependencies are recorded in the target caches of dependenciesCache, which may be nil. Returns the ids of the missing dependencies, sorted.
+func BackfillArtifactory(dependencies map[string]bool, cachePath string, resolverDeployer *params.ResolverDeployer, dependenciesCache *cache.DependenciesCache, options ...utils.Option) ([]string, error) {
+	logger := utils.NewOptions(options...).GetLogger()
+	deployer := resolverDeployer.Deployer()
+	if deployer == nil || deployer.IsEmpty() {
+		return nil, errorutils.CheckError(errors.New("a deployer is required for backfilling Artifactory"))
+	}
+	resolver := resolverDeployer.Resolver()
+	if resolver == nil || resolver.IsEmpty() {
+		resolver = deployer
+	}
+	if dependenciesCache == nil {
+		dependenciesCache = &cache.DependenciesCache{}
+	}
+	packages, err := GetDependencies(cachePath, dependencies, options...)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].GetId() < packages[j].GetId()
+	})
+	missing, backfillErrors, err := getMissingPackages(packages, resolver, logger)
+	if err != nil {
+		return nil, err
+	}
+	var missingIds []string
+	for i := range missing {
+		missingIds = append(missingIds, missing[i].GetId())
+	}
+	logger.Info(fmt.Sprintf("%d out of %d dependencies are missing from %s, and will be published to %s", len(missing), len(packages), resolver.Repo(), deployer.Repo()))
+	backfillError := &BackfillError{Errors: backfillErrors}
+	if len(missing) > 0 {
+		err = PublishToDeployers(missing, resolverDeployer, dependenciesCache, options...)
+		if err != nil && !errors.As(err, &backfillError.PublishErr) {
+			return nil, err
+		}
+	}
+	if len(backfillError.Errors) > 0 || backfillError.PublishErr != nil {
+		return missingIds, backfillError
+	}
+	return missingIds, nil
+}
+
+// Sends a HEAD request for each of the packages to the resolver repository, and returns the packages which were not found.
+// The packages which couldn't be checked are returned in the errors map.
+func getMissingPackages(packages []Package, resolver *params.Params, logger log.Log) ([]Package, map[string]error, error) {
+	client, err := httpclient.ClientBuilder().Build()
+	if err != nil {
+		return nil, nil, errorutils.CheckError(err)
+	}
+	serviceDetails := resolver.ServiceManager().GetConfig().GetServiceDetails()
+	var missing []Package
+	checkErrors := map[string]error{}
+	for i := range packages {
+		idParts := strings.Split(packages[i].GetId(), ":")
+		resp, err := performHeadRequest(serviceDetails, client, resolver.Repo(), idParts[0], packages[i].version, logger)
+		if err != nil {
+			checkErrors[packages[i].GetId()] = errorutils.CheckError(err)
+			continue
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			logger.Debug(fmt.Sprintf("%s exists in %s", packages[i].GetId(), resolver.Repo()))
+		case http.StatusNotFound, http.StatusGone:
+			missing = append(missing, packages[i])
+		default:
+			err = utils.NewStatusError(resp.StatusCode, goModDecode(idParts[0]), goModDecode(packages[i].version), serviceDetails.GetUrl())
+			if err == nil {
+				err = errorutils.CheckError(errors.New("Artifactory response: " + resp.Status))
+			}
+			checkErrors[packages[i].GetId()] = err
+		}
+	}
+	return missing, checkErrors, nil
+}
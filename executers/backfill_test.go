@@ -0,0 +1,85 @@
+package executers
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/params"
+	"github.com/jfrog/gocmd/tests/artifactorytest"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfillArtifactory(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	assert.NoError(t, server.AddRepo("go-virtual"))
+	assert.NoError(t, server.AddModule("go-virtual", "rsc.io/quote", "v1.5.2", map[string][]byte{".mod": []byte(testModContent)}))
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	createTestModuleZip(t, cachePath, "example.com/hello", "v1.0.0", map[string]string{"go.mod": "module example.com/hello\n"})
+	versionDir := filepath.Join(cachePath, "example.com", "hello", "@v")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(versionDir, "v1.0.0.mod"), []byte("module example.com/hello\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(versionDir, "v1.0.0.info"), []byte(`{"Version":"v1.0.0"}`), 0644))
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+
+	resolverDeployer := &params.ResolverDeployer{}
+	resolverDeployer.SetResolver((&params.Params{}).SetRepo("go-virtual").SetServiceManager(servicesManager))
+	resolverDeployer.SetDeployer((&params.Params{}).SetRepo("go-local").SetServiceManager(servicesManager))
+	dependencies := map[string]bool{"rsc.io/quote@v1.5.2": true, "example.com/hello@v1.0.0": true}
+	dependenciesCache := &cache.DependenciesCache{}
+	buffer := &bytes.Buffer{}
+	missing, err := BackfillArtifactory(dependencies, cachePath, resolverDeployer, dependenciesCache, utils.WithLogger(log.NewLogger(log.INFO, buffer)))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"example.com/hello:v1.0.0"}, missing)
+	assert.Contains(t, buffer.String(), "1 out of 2 dependencies are missing from go-virtual, and will be published to go-local")
+	assert.Len(t, server.Uploads(), 3)
+	for _, upload := range server.Uploads() {
+		assert.Equal(t, "go-local", upload.Repo)
+		assert.Equal(t, "example.com/hello", upload.Module)
+	}
+	assert.True(t, dependenciesCache.GetTarget(GetDeployerTargetName(resolverDeployer.Deployer())).GetMap()["example.com/hello:v1.0.0"])
+	assert.Equal(t, 1, dependenciesCache.GetReport().Published)
+
+	// The missing dependencies are published to all the deployers.
+	dr := createArtifactoryTestServer(t)
+	defer dr.Close()
+	drManager, err := dr.ServicesManager()
+	assert.NoError(t, err)
+	resolverDeployer.AddDeployer((&params.Params{}).SetRepo("go-local").SetServiceManager(drManager))
+	dr.SetCredentials(artifactorytest.DefaultUser, "wrong")
+	_, err = BackfillArtifactory(dependencies, cachePath, resolverDeployer, nil)
+	var partialErr *PartialPublishError
+	if assert.True(t, errors.As(err, &partialErr)) {
+		assert.Contains(t, partialErr.Errors[GetDeployerTargetName(resolverDeployer.Deployers()[1])], "example.com/hello:v1.0.0")
+	}
+	dr.SetCredentials(artifactorytest.DefaultUser, artifactorytest.DefaultPassword)
+	_, err = BackfillArtifactory(dependencies, cachePath, resolverDeployer, nil)
+	assert.NoError(t, err)
+	assert.Len(t, dr.Uploads(), 3)
+	resolverDeployer.SetDeployer(resolverDeployer.Deployer())
+
+	// The resolver is checked with the resolver credentials, and failures are reported per dependency.
+	server.SetCredentials(artifactorytest.DefaultUser, "wrong")
+	_, err = BackfillArtifactory(dependencies, cachePath, resolverDeployer, nil)
+	var backfillErr *BackfillError
+	if assert.True(t, errors.As(err, &backfillErr)) {
+		assert.Len(t, backfillErr.Errors, 2)
+		var authErr *utils.AuthenticationError
+		if assert.True(t, errors.As(backfillErr.Errors["rsc.io/quote:v1.5.2"], &authErr)) {
+			assert.Equal(t, http.StatusUnauthorized, authErr.StatusCode)
+		}
+	}
+
+	// A deployer is required.
+	_, err = BackfillArtifactory(dependencies, cachePath, &params.ResolverDeployer{}, nil)
+	assert.Error(t, err)
+}
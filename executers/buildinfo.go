@@ -0,0 +1,39 @@
+package executers
+
+import (
+	"fmt"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// Adds the properties to the properties of the build-info module.
+// The existing properties are kept, and must be a map of strings, or a map of values such as the one decoded from a build-info JSON.
+func addModuleProperties(module *buildinfo.Module, properties map[string]string) error {
+	if len(properties) == 0 {
+		return nil
+	}
+	switch moduleProperties := module.Properties.(type) {
+	case nil:
+		module.Properties = properties
+	case map[string]string:
+		if moduleProperties == nil {
+			module.Properties = properties
+			return nil
+		}
+		for key, value := range properties {
+			moduleProperties[key] = value
+		}
+	case map[string]interface{}:
+		if moduleProperties == nil {
+			module.Properties = properties
+			return nil
+		}
+		for key, value := range properties {
+			moduleProperties[key] = value
+		}
+	default:
+		return errorutils.CheckError(fmt.Errorf("the properties of the build-info module %s are of type %T, and can't be merged with the added properties", module.Id, module.Properties))
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package executers
+
+import (
+	"testing"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddModuleProperties(t *testing.T) {
+	properties := map[string]string{"go.licenses.github.com/jfrog/lib@v1.0.0": "MIT"}
+
+	module := &buildinfo.Module{Id: "github.com/jfrog/main"}
+	assert.NoError(t, addModuleProperties(module, properties))
+	assert.Equal(t, properties, module.Properties)
+
+	// Properties decoded from a build-info JSON are merged.
+	module = &buildinfo.Module{Id: "github.com/jfrog/main", Properties: map[string]interface{}{"key": "value"}}
+	assert.NoError(t, addModuleProperties(module, properties))
+	assert.Equal(t, map[string]interface{}{"key": "value", "go.licenses.github.com/jfrog/lib@v1.0.0": "MIT"}, module.Properties)
+
+	// Properties which can't be merged are not dropped.
+	module = &buildinfo.Module{Id: "github.com/jfrog/main", Properties: []string{"value"}}
+	assert.Error(t, addModuleProperties(module, properties))
+	assert.Equal(t, []string{"value"}, module.Properties)
+}
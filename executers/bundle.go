@@ -0,0 +1,377 @@
+package executers
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/params"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"golang.org/x/mod/module"
+)
+
+const (
+	BundleManifestName    = "manifest.json"
+	BundleManifestVersion = 1
+	// The directory of the module files in the bundle, which has the layout of the 'cache/download' directory of the module cache.
+	bundleDownloadDir = "cache/download"
+)
+
+// The manifest of an offline bundle, which lists the modules in the bundle and the checksums of their files.
+type BundleManifest struct {
+	Version int            `json:"version"`
+	Modules []BundleModule `json:"modules"`
+}
+
+type BundleModule struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// The h1 hashes of the module, as recorded in go.sum. ZipHash is empty if the bundle includes only the go.mod file of the module.
+	ZipHash   string       `json:"zipHash,omitempty"`
+	GoModHash string       `json:"goModHash"`
+	Files     []BundleFile `json:"files"`
+}
+
+type BundleFile struct {
+	// The path of the file in the bundle.
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// Exports the dependencies, which are in the format returned by cmd.GetDependenciesList, from the cache into a tar.gz bundle.
+// The bundle includes the .info, .mod and .zip files of the dependencies in the layout of the cache, and a manifest with their checksums.
+// The .mod file of each dependency must exist in the cache. The .zip file is exported if it exists, since the go command doesn't download the zips of modules which aren't built.
+func ExportBundle(dependencies map[string]bool, cachePath, bundlePath string, options ...utils.Option) (*BundleManifest, error) {
+	var sortedDependencies []string
+	for dependency := range dependencies {
+		if _, version := splitDependency(dependency); version != "" {
+			sortedDependencies = append(sortedDependencies, dependency)
+		}
+	}
+	sort.Strings(sortedDependencies)
+	manifest := &BundleManifest{Version: BundleManifestVersion}
+	for _, dependency := range sortedDependencies {
+		modulePath, version := splitDependency(dependency)
+		bundleModule, err := getBundleModule(cachePath, modulePath, version)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Modules = append(manifest.Modules, *bundleModule)
+	}
+	err := writeBundle(manifest, cachePath, bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	utils.NewOptions(options...).GetLogger().Info(fmt.Sprintf("Exported %d modules to %s", len(manifest.Modules), bundlePath))
+	return manifest, nil
+}
+
+// Returns the manifest entry of the module version in the cache.
+func getBundleModule(cachePath, modulePath, version string) (*BundleModule, error) {
+	versionPath, err := getBundleVersionPath(modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	bundleModule := &BundleModule{Path: modulePath, Version: version}
+	for _, ext := range []string{".info", ".mod", ".zip"} {
+		name := versionPath + ext
+		filePath := filepath.Join(cachePath, filepath.FromSlash(strings.TrimPrefix(name, bundleDownloadDir+"/")))
+		exists, err := fileutils.IsFileExists(filePath, false)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if ext == ".mod" {
+				return nil, errorutils.CheckError(errors.New(fmt.Sprintf("The go.mod file of %s@%s is missing from the cache: %s", modulePath, version, filePath)))
+			}
+			continue
+		}
+		bundleFile, err := getBundleFile(name, filePath)
+		if err != nil {
+			return nil, err
+		}
+		bundleModule.Files = append(bundleModule.Files, *bundleFile)
+		switch ext {
+		case ".mod":
+			content, err := ioutil.ReadFile(filePath)
+			if err != nil {
+				return nil, errorutils.CheckError(err)
+			}
+			bundleModule.GoModHash, err = utils.HashGoMod(content)
+		case ".zip":
+			bundleModule.ZipHash, err = utils.HashZip(filePath)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bundleModule, nil
+}
+
+// Returns the path of the module version files in the bundle, without the file extension.
+func getBundleVersionPath(modulePath, version string) (string, error) {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return path.Join(bundleDownloadDir, escapedPath, "@v", escapedVersion), nil
+}
+
+func getBundleFile(name, filePath string) (*BundleFile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return &BundleFile{Name: name, Size: size, Sha256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+func writeBundle(manifest *BundleManifest, cachePath, bundlePath string) (err error) {
+	bundleFile, err := os.Create(bundlePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		e := bundleFile.Close()
+		if err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	gzipWriter := gzip.NewWriter(bundleFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	// The manifest is written first, so it can be read before the module files.
+	err = tarWriter.WriteHeader(&tar.Header{Name: BundleManifestName, Mode: 0644, Size: int64(len(manifestContent))})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if _, err = tarWriter.Write(manifestContent); err != nil {
+		return errorutils.CheckError(err)
+	}
+	for _, bundleModule := range manifest.Modules {
+		for _, file := range bundleModule.Files {
+			filePath := filepath.Join(cachePath, filepath.FromSlash(strings.TrimPrefix(file.Name, bundleDownloadDir+"/")))
+			if err = addBundleFile(tarWriter, file, filePath); err != nil {
+				return err
+			}
+		}
+	}
+	if err = tarWriter.Close(); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(gzipWriter.Close())
+}
+
+func addBundleFile(tarWriter *tar.Writer, file BundleFile, filePath string) error {
+	content, err := os.Open(filePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer content.Close()
+	err = tarWriter.WriteHeader(&tar.Header{Name: file.Name, Mode: 0644, Size: file.Size})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = io.CopyN(tarWriter, content, file.Size)
+	return errorutils.CheckError(err)
+}
+
+// Imports a bundle created by ExportBundle into the cache, after verifying the checksums of its files against the manifest.
+// Files which already exist in the cache are not overwritten.
+func ImportBundleToCache(bundlePath, cachePath string, options ...utils.Option) (*BundleManifest, error) {
+	logger := utils.NewOptions(options...).GetLogger()
+	manifest, bundleDir, err := extractBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fileutils.RemoveTempDir(bundleDir)
+	for _, bundleModule := range manifest.Modules {
+		for _, file := range bundleModule.Files {
+			targetPath := filepath.Join(cachePath, filepath.FromSlash(strings.TrimPrefix(file.Name, bundleDownloadDir+"/")))
+			exists, err := fileutils.IsFileExists(targetPath, false)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				logger.Debug("The file already exists in the cache:", targetPath)
+				continue
+			}
+			if err = fileutils.CopyFile(filepath.Dir(targetPath), filepath.Join(bundleDir, filepath.FromSlash(file.Name))); err != nil {
+				return nil, err
+			}
+		}
+	}
+	logger.Info(fmt.Sprintf("Imported %d modules from %s to %s", len(manifest.Modules), bundlePath, cachePath))
+	return manifest, nil
+}
+
+// Imports a bundle created by ExportBundle into Artifactory, after verifying the checksums of its files against the manifest.
+// The modules are published to all the deployers like the dependencies of a project, see PublishToDeployers, and recorded in the target caches of dependenciesCache, which may be nil.
+// Modules which include only a go.mod file in the bundle can't be published, and are skipped.
+func ImportBundleToArtifactory(bundlePath string, resolverDeployer *params.ResolverDeployer, dependenciesCache *cache.DependenciesCache, options ...utils.Option) (*BundleManifest, error) {
+	opts := utils.NewOptions(options...)
+	logger := opts.GetLogger()
+	manifest, bundleDir, err := extractBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fileutils.RemoveTempDir(bundleDir)
+	bundleCachePath := filepath.Join(bundleDir, filepath.FromSlash(bundleDownloadDir))
+	var packages []Package
+	for _, bundleModule := range manifest.Modules {
+		if bundleModule.ZipHash == "" {
+			logger.Warn(fmt.Sprintf("Skipping %s@%s, since the bundle doesn't include its zip", bundleModule.Path, bundleModule.Version))
+			continue
+		}
+		dependencyPackage, err := createDependency(bundleCachePath, goModEncode(bundleModule.Path), goModEncode(bundleModule.Version), opts)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, *dependencyPackage)
+	}
+	return manifest, PublishToDeployers(packages, resolverDeployer, dependenciesCache, options...)
+}
+
+// Extracts the bundle into a temp directory, and verifies the extracted files against the manifest.
+// Returns the manifest and the temp directory, which should be removed by the caller.
+func extractBundle(bundlePath string) (manifest *BundleManifest, bundleDir string, err error) {
+	bundleDir, err = fileutils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			fileutils.RemoveTempDir(bundleDir)
+		}
+	}()
+	bundleFile, err := os.Open(bundlePath)
+	if err != nil {
+		err = errorutils.CheckError(err)
+		return
+	}
+	defer bundleFile.Close()
+	gzipReader, err := gzip.NewReader(bundleFile)
+	if err != nil {
+		err = errorutils.CheckError(err)
+		return
+	}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		var header *tar.Header
+		header, err = tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			err = errorutils.CheckError(err)
+			return
+		}
+		if header.Name == BundleManifestName {
+			manifest = &BundleManifest{}
+			if err = errorutils.CheckError(json.NewDecoder(tarReader).Decode(manifest)); err != nil {
+				return
+			}
+			continue
+		}
+		if err = extractBundleFile(tarReader, header, bundleDir); err != nil {
+			return
+		}
+	}
+	if manifest == nil {
+		err = errorutils.CheckError(errors.New("The bundle doesn't include a manifest: " + bundlePath))
+		return
+	}
+	err = verifyBundle(manifest, bundleDir)
+	return
+}
+
+func extractBundleFile(tarReader *tar.Reader, header *tar.Header, bundleDir string) error {
+	name := path.Clean(header.Name)
+	if header.Typeflag != tar.TypeReg || !strings.HasPrefix(name, bundleDownloadDir+"/") || strings.Contains(name, "..") {
+		return errorutils.CheckError(errors.New("Unexpected file in the bundle: " + header.Name))
+	}
+	targetPath := filepath.Join(bundleDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	file, err := os.Create(targetPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer file.Close()
+	_, err = io.Copy(file, tarReader)
+	return errorutils.CheckError(err)
+}
+
+// Verifies that the extracted files match the manifest: the files listed in the manifest exist with the same checksums,
+// the h1 hashes of the modules match, and there are no files which are not listed.
+func verifyBundle(manifest *BundleManifest, bundleDir string) error {
+	listed := map[string]bool{}
+	for _, bundleModule := range manifest.Modules {
+		versionPath, err := getBundleVersionPath(bundleModule.Path, bundleModule.Version)
+		if err != nil {
+			return err
+		}
+		for _, file := range bundleModule.Files {
+			if !strings.HasPrefix(file.Name, versionPath+".") {
+				return errorutils.CheckError(errors.New(fmt.Sprintf("The file %s doesn't belong to %s@%s", file.Name, bundleModule.Path, bundleModule.Version)))
+			}
+			listed[file.Name] = true
+			actual, err := getBundleFile(file.Name, filepath.Join(bundleDir, filepath.FromSlash(file.Name)))
+			if err != nil {
+				return err
+			}
+			if actual.Sha256 != file.Sha256 {
+				return &utils.ChecksumMismatchError{Module: bundleModule.Path, Version: bundleModule.Version, File: file.Name, Expected: file.Sha256, Actual: actual.Sha256}
+			}
+		}
+		actualModule, err := getBundleModule(filepath.Join(bundleDir, filepath.FromSlash(bundleDownloadDir)), bundleModule.Path, bundleModule.Version)
+		if err != nil {
+			return err
+		}
+		err = verifyHashes(bundleModule.Path, bundleModule.Version, "", bundleModule.ZipHash, bundleModule.GoModHash, actualModule.ZipHash, actualModule.GoModHash)
+		if err != nil {
+			return err
+		}
+	}
+	return filepath.Walk(bundleDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relativePath, err := filepath.Rel(bundleDir, filePath)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		if !listed[filepath.ToSlash(relativePath)] {
+			return errorutils.CheckError(errors.New("The bundle includes a file which is not listed in its manifest: " + filepath.ToSlash(relativePath)))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+package executers
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/params"
+	"github.com/jfrog/gocmd/tests/artifactorytest"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	// A module which only its go.mod file was downloaded.
+	samplerDir := filepath.Join(cachePath, "rsc.io", "sampler", "@v")
+	assert.NoError(t, os.MkdirAll(samplerDir, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(samplerDir, "v1.3.0.mod"), []byte("module rsc.io/sampler\n"), 0644))
+	tempDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer fileutils.RemoveTempDir(tempDir)
+
+	bundlePath := filepath.Join(tempDir, "bundle.tar.gz")
+	manifest, err := ExportBundle(map[string]bool{"rsc.io/quote@v1.5.2": true, "rsc.io/sampler@v1.3.0": true, "example.com/main@": true}, cachePath, bundlePath)
+	assert.NoError(t, err)
+	if assert.Len(t, manifest.Modules, 2) {
+		assert.Equal(t, "rsc.io/quote", manifest.Modules[0].Path)
+		assert.Len(t, manifest.Modules[0].Files, 3)
+		zipHash, err := utils.HashZip(filepath.Join(cachePath, "rsc.io", "quote", "@v", "v1.5.2.zip"))
+		assert.NoError(t, err)
+		assert.Equal(t, zipHash, manifest.Modules[0].ZipHash)
+		assert.Equal(t, []BundleFile{{Name: "cache/download/rsc.io/sampler/@v/v1.3.0.mod", Size: 22, Sha256: manifest.Modules[1].Files[0].Sha256}}, manifest.Modules[1].Files)
+		assert.Empty(t, manifest.Modules[1].ZipHash)
+	}
+
+	// Import into an empty cache.
+	importedCachePath := filepath.Join(tempDir, "cache")
+	imported, err := ImportBundleToCache(bundlePath, importedCachePath)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, imported)
+	for _, file := range []string{"rsc.io/quote/@v/v1.5.2.zip", "rsc.io/quote/@v/v1.5.2.mod", "rsc.io/quote/@v/v1.5.2.info", "rsc.io/sampler/@v/v1.3.0.mod"} {
+		assert.FileExists(t, filepath.Join(importedCachePath, filepath.FromSlash(file)))
+	}
+
+	// Import into Artifactory, to all the deployers. The module without a zip is skipped.
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+	dr := createArtifactoryTestServer(t)
+	defer dr.Close()
+	drManager, err := dr.ServicesManager()
+	assert.NoError(t, err)
+	resolverDeployer := &params.ResolverDeployer{}
+	resolverDeployer.SetDeployer((&params.Params{}).SetRepo("go-local").SetServiceManager(servicesManager))
+	resolverDeployer.AddDeployer((&params.Params{}).SetRepo("go-local").SetServiceManager(drManager))
+	dependenciesCache := &cache.DependenciesCache{}
+	_, err = ImportBundleToArtifactory(bundlePath, resolverDeployer, dependenciesCache)
+	assert.NoError(t, err)
+	for _, target := range []*artifactorytest.Server{server, dr} {
+		uploads := target.Uploads()
+		if assert.Len(t, uploads, 3) {
+			assert.Equal(t, "rsc.io/quote", uploads[0].Module)
+		}
+	}
+	for _, deployer := range resolverDeployer.Deployers() {
+		targetCache := dependenciesCache.GetTarget(GetDeployerTargetName(deployer))
+		assert.True(t, targetCache.GetMap()["rsc.io/quote:v1.5.2"])
+		assert.Equal(t, 1, targetCache.GetSuccesses())
+	}
+
+	// A file which doesn't match the manifest fails the import.
+	manifest.Modules[0].Files[1].Sha256 = "0000"
+	tamperedPath := filepath.Join(tempDir, "tampered.tar.gz")
+	assert.NoError(t, writeBundle(manifest, cachePath, tamperedPath))
+	_, err = ImportBundleToCache(tamperedPath, filepath.Join(tempDir, "tampered"))
+	var checksumErr *utils.ChecksumMismatchError
+	if assert.True(t, errors.As(err, &checksumErr)) {
+		assert.Equal(t, "0000", checksumErr.Expected)
+	}
+	assert.NoDirExists(t, filepath.Join(tempDir, "tampered"))
+}
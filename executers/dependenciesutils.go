@@ -1,7 +1,9 @@
 package executers
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -9,6 +11,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/jfrog/gocmd/executers/utils"
 	"github.com/jfrog/jfrog-client-go/auth"
 	"github.com/jfrog/jfrog-client-go/http/httpclient"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
@@ -18,17 +21,17 @@ import (
 )
 
 const (
-	FailedToRetrieve          = "Failed to retrieve"
-	FromBothArtifactoryAndVcs = "from both Artifactory and VCS"
+	FailedToRetrieve          = utils.FailedToRetrieve
+	FromBothArtifactoryAndVcs = utils.FromBothArtifactoryAndVcs
 )
 
-func performHeadRequest(auth auth.ServiceDetails, client *httpclient.HttpClient, targetRepo, module, version string) (*http.Response, error) {
+func performHeadRequest(auth auth.ServiceDetails, client *httpclient.HttpClient, targetRepo, module, version string, logger log.Log) (*http.Response, error) {
 	url := auth.GetUrl() + "api/go/" + targetRepo + "/" + module + "/@v/" + version + ".mod"
 	resp, _, err := client.SendHead(url, auth.CreateHttpClientDetails(), "")
 	if err != nil {
 		return nil, err
 	}
-	log.Debug("Artifactory head request response for", url, ":", resp.StatusCode)
+	logger.Debug("Artifactory head request response for", url, ":", resp.StatusCode)
 	return resp, nil
 }
 
@@ -79,39 +82,72 @@ func goModDecode(name string) string {
 	return str
 }
 
-// Downloads the mod file from Artifactory to the Go cache
-func downloadModFileFromArtifactoryToLocalCache(cachePath, targetRepo, name, version string, auth auth.ServiceDetails, client *httpclient.HttpClient) string {
+// Downloads the mod file from Artifactory to the Go cache.
+// Returns an empty path if the module directory doesn't exist in the cache.
+func downloadModFileFromArtifactoryToLocalCache(cachePath, targetRepo, name, version string, auth auth.ServiceDetails, client *httpclient.HttpClient, opts *utils.Options) (string, error) {
+	logger := opts.GetLogger()
 	pathToModuleCache := filepath.Join(cachePath, name, "@v")
 	dirExists, err := fileutils.IsDirExists(pathToModuleCache, false)
 	if err != nil {
-		log.Error(fmt.Sprintf("Received an error: %s for %s@%s", err, name, version))
-		return ""
+		logger.Error(fmt.Sprintf("Received an error: %s for %s@%s", err, name, version))
+		return "", err
 	}
 
 	if dirExists {
 		url := auth.GetUrl() + "api/go/" + targetRepo + "/" + name + "/@v/" + version + ".mod"
-		log.Debug("Downloading mod file from Artifactory:", url)
-		downloadFileDetails := &httpclient.DownloadFileDetails{
-			FileName: version + ".mod",
-			// Artifactory URL
-			DownloadPath:  url,
-			LocalPath:     pathToModuleCache,
-			LocalFileName: version + ".mod",
-		}
-		resp, err := client.DownloadFile(downloadFileDetails, "", auth.CreateHttpClientDetails(), false)
+		logger.Debug("Downloading mod file from Artifactory:", url)
+		observer := opts.GetObserver()
+		event := utils.ProgressEvent{Operation: utils.DownloadOperation, ModuleId: name + ":" + version, Repo: targetRepo}
+		observer.ModuleStarted(event)
+		modPath := filepath.Join(pathToModuleCache, version+".mod")
+		event.Bytes, err = downloadFile(url, modPath, name, version, auth, client, observer, event)
 		if err != nil {
-			log.Error(fmt.Sprintf("Received an error %s downloading a file: %s to the local path: %s", err.Error(), downloadFileDetails.FileName, downloadFileDetails.LocalPath))
-			return ""
+			logger.Error(fmt.Sprintf("Received an error %s downloading a file: %s to the local path: %s", err.Error(), version+".mod", pathToModuleCache))
+			event.Err = err
+			observer.ModuleFailed(event)
+			return "", err
 		}
+		logger.Debug(fmt.Sprintf("Downloaded %d bytes from Artifactory %s", event.Bytes, url))
+		observer.ModuleFinished(event)
+		return modPath, nil
+	}
+	return "", nil
+}
 
-		log.Debug(fmt.Sprintf("Received %d from Artifactory %s", resp.StatusCode, url))
-		return filepath.Join(downloadFileDetails.LocalPath, downloadFileDetails.LocalFileName)
+// Downloads the file from Artifactory to the local path, and reports the bytes to the observer as they are read. Returns the number of bytes downloaded.
+func downloadFile(url, localPath, name, version string, auth auth.ServiceDetails, client *httpclient.HttpClient, observer utils.Observer, event utils.ProgressEvent) (int64, error) {
+	reader, resp, err := client.ReadRemoteFile(url, auth.CreateHttpClientDetails())
+	if err != nil {
+		return 0, errorutils.CheckError(err)
+	}
+	if reader == nil {
+		if resp.Body != nil {
+			resp.Body.Close()
+		}
+		err = utils.NewStatusError(resp.StatusCode, goModDecode(name), goModDecode(version), url)
+		if err == nil {
+			err = errorutils.CheckError(errors.New("Artifactory response: " + resp.Status))
+		}
+		return 0, err
+	}
+	defer reader.Close()
+	file, err := os.Create(localPath)
+	if err != nil {
+		return 0, errorutils.CheckError(err)
+	}
+	written, err := io.Copy(file, utils.NewProgressReader(reader, observer, event))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave a partial file in the cache.
+		os.Remove(localPath)
 	}
-	return ""
+	return written, errorutils.CheckError(err)
 }
 
-func shouldDownloadFromArtifactory(module, version, targetRepo string, auth auth.ServiceDetails, client *httpclient.HttpClient) (bool, error) {
-	res, err := performHeadRequest(auth, client, targetRepo, module, version)
+func shouldDownloadFromArtifactory(module, version, targetRepo string, auth auth.ServiceDetails, client *httpclient.HttpClient, logger log.Log) (bool, error) {
+	res, err := performHeadRequest(auth, client, targetRepo, module, version, logger)
 	if err != nil {
 		return false, err
 	}
@@ -121,12 +157,15 @@ func shouldDownloadFromArtifactory(module, version, targetRepo string, auth auth
 	return false, nil
 }
 
-func GetDependencies(cachePath string, moduleSlice map[string]bool) ([]Package, error) {
+// Returns the packages of the dependencies which have a zip in the cache.
+// The logger and observer of the options are set on the packages. See Package.SetLogger and Package.SetObserver.
+func GetDependencies(cachePath string, moduleSlice map[string]bool, options ...utils.Option) ([]Package, error) {
+	opts := utils.NewOptions(options...)
 	var deps []Package
 	for module := range moduleSlice {
 		moduleInfo := strings.Split(module, "@")
 		name := goModEncode(moduleInfo[0])
-		dep, err := createDependency(cachePath, name, goModEncode(moduleInfo[1]))
+		dep, err := createDependency(cachePath, name, goModEncode(moduleInfo[1]), opts)
 		if err != nil {
 			return nil, err
 		}
@@ -139,10 +178,10 @@ func GetDependencies(cachePath string, moduleSlice map[string]bool) ([]Package,
 
 // Creates a go dependency.
 // Returns a nil value in case the dependency does not include a zip in the cache.
-func createDependency(cachePath, dependencyName, version string) (*Package, error) {
+func createDependency(cachePath, dependencyName, version string, opts *utils.Options) (*Package, error) {
 	// We first check if the this dependency has a zip binary in the local go cache.
 	// If it does not, nil is returned. This seems to be a bug in go.
-	zipPath, err := getPackageZipLocation(cachePath, dependencyName, version)
+	zipPath, err := getPackageZipLocation(cachePath, dependencyName, version, opts.GetLogger())
 
 	if err != nil {
 		return nil, err
@@ -153,7 +192,7 @@ func createDependency(cachePath, dependencyName, version string) (*Package, erro
 	}
 
 	dep := Package{}
-
+	dep.setOptions(opts)
 	dep.id = strings.Join([]string{dependencyName, version}, ":")
 	dep.version = version
 	dep.zipPath = zipPath
@@ -168,8 +207,8 @@ func createDependency(cachePath, dependencyName, version string) (*Package, erro
 }
 
 // Returns the path to the package zip file if exists.
-func getPackageZipLocation(cachePath, dependencyName, version string) (string, error) {
-	zipPath, err := getPackagePathIfExists(cachePath, dependencyName, version)
+func getPackageZipLocation(cachePath, dependencyName, version string, logger log.Log) (string, error) {
+	zipPath, err := getPackagePathIfExists(cachePath, dependencyName, version, logger)
 	if err != nil {
 		return "", err
 	}
@@ -178,7 +217,7 @@ func getPackageZipLocation(cachePath, dependencyName, version string) (string, e
 		return zipPath, nil
 	}
 
-	zipPath, err = getPackagePathIfExists(filepath.Dir(cachePath), dependencyName, version)
+	zipPath, err = getPackagePathIfExists(filepath.Dir(cachePath), dependencyName, version, logger)
 
 	if err != nil {
 		return "", err
@@ -188,37 +227,21 @@ func getPackageZipLocation(cachePath, dependencyName, version string) (string, e
 }
 
 // Validates if the package zip file exists.
-func getPackagePathIfExists(cachePath, dependencyName, version string) (zipPath string, err error) {
+func getPackagePathIfExists(cachePath, dependencyName, version string, logger log.Log) (zipPath string, err error) {
 	zipPath = filepath.Join(cachePath, dependencyName, "@v", version+".zip")
 	fileExists, err := fileutils.IsFileExists(zipPath, false)
 	if err != nil {
-		log.Warn(fmt.Sprintf("Could not find zip binary for dependency '%s' at %s.", dependencyName, zipPath))
+		logger.Warn(fmt.Sprintf("Could not find zip binary for dependency '%s' at %s.", dependencyName, zipPath))
 		return "", err
 	}
 	// Zip binary does not exist, so we skip it by returning a nil dependency.
 	if !fileExists {
-		log.Debug("The following file is missing:", zipPath)
+		logger.Debug("The following file is missing:", zipPath)
 		return "", nil
 	}
 	return zipPath, nil
 }
 
-func removeGoSum(path string) error {
-	// Remove go.sum file to avoid checksum conflicts with the old go.sum
-	goSum := filepath.Join(filepath.Dir(path), "go.sum")
-	exists, err := fileutils.IsFileExists(goSum, false)
-	if err != nil {
-		return err
-	}
-	if exists {
-		err = os.Remove(goSum)
-		if errorutils.CheckError(err) != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 type previousTries struct {
 	triedFromArtifactory bool
 	triedFromVCS         bool
@@ -231,3 +254,14 @@ func (pt *previousTries) setTriedFrom(usedProxy bool) {
 		pt.triedFromVCS = true
 	}
 }
+
+// Returns the typed error which describes the failure to retrieve the module from the sources it was tried from.
+func (pt *previousTries) newError(modulePath, version string, artifactoryErr, vcsErr error) error {
+	switch {
+	case pt.triedFromArtifactory && pt.triedFromVCS:
+		return &utils.BothSourcesError{Module: modulePath, Version: version, ArtifactoryErr: artifactoryErr, VcsErr: &utils.VcsFallbackError{Module: modulePath, Version: version, Err: vcsErr}}
+	case pt.triedFromVCS:
+		return &utils.VcsFallbackError{Module: modulePath, Version: version, Err: vcsErr}
+	}
+	return artifactoryErr
+}
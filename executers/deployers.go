@@ -0,0 +1,84 @@
+package executers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/params"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// Returned by PublishToDeployers when some of the packages couldn't be published to some of the deployers.
+type PartialPublishError struct {
+	// The errors by the target name of the deployer, and by the package id.
+	Errors map[string]map[string]error
+	// The target names of the deployers which all the packages were published to.
+	Succeeded []string
+}
+
+func (partialError *PartialPublishError) Error() string {
+	var messages []string
+	for target, errs := range partialError.Errors {
+		for id, err := range errs {
+			messages = append(messages, fmt.Sprintf("%s: %s: %s", target, id, err.Error()))
+		}
+	}
+	sort.Strings(messages)
+	return fmt.Sprintf("Publishing failed for %d out of %d deployers:\n%s", len(partialError.Errors), len(partialError.Errors)+len(partialError.Succeeded), strings.Join(messages, "\n"))
+}
+
+// Returns the name which identifies the deployer in the cache and in the publish report: the Artifactory URL followed by the repository.
+func GetDeployerTargetName(deployer *params.Params) string {
+	return clientutils.AddTrailingSlashIfNeeded(deployer.ServiceManager().GetConfig().GetServiceDetails().GetUrl()) + deployer.Repo()
+}
+
+// Publishes the packages to all the deployers, such as a primary Artifactory and a disaster recovery instance.
+// Each package is published to every deployer, even if publishing it to another deployer failed.
+// The results of each deployer are tracked in the target cache of dependenciesCache, which may be nil, by the target name of the deployer.
+// A PartialPublishError is returned if some of the packages failed to be published to some of the deployers.
+func PublishToDeployers(packages []Package, resolverDeployer *params.ResolverDeployer, dependenciesCache *cache.DependenciesCache, options ...utils.Option) error {
+	logger := utils.NewOptions(options...).GetLogger()
+	var deployers []*params.Params
+	for _, deployer := range resolverDeployer.Deployers() {
+		if deployer != nil && !deployer.IsEmpty() {
+			deployers = append(deployers, deployer)
+		}
+	}
+	if len(deployers) == 0 {
+		return errorutils.CheckError(errors.New("at least one deployer is required for publishing"))
+	}
+	if dependenciesCache == nil {
+		dependenciesCache = &cache.DependenciesCache{}
+	}
+	partialError := &PartialPublishError{Errors: map[string]map[string]error{}}
+	for _, deployer := range deployers {
+		target := GetDeployerTargetName(deployer)
+		targetCache := dependenciesCache.GetTarget(target)
+		targetCache.IncrementTotal(len(packages))
+		logger.Info(fmt.Sprintf("Publishing %d packages to %s", len(packages), target))
+		targetErrors := map[string]error{}
+		for i := range packages {
+			err := packages[i].PopulateModAndPublish(deployer.Repo(), targetCache, deployer.ServiceManager())
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed publishing %s to %s: %s", packages[i].GetId(), target, err.Error()))
+				targetErrors[packages[i].GetId()] = err
+				continue
+			}
+			targetCache.GetMap()[packages[i].GetId()] = true
+		}
+		if len(targetErrors) > 0 {
+			partialError.Errors[target] = targetErrors
+		} else {
+			partialError.Succeeded = append(partialError.Succeeded, target)
+		}
+	}
+	if len(partialError.Errors) > 0 {
+		return partialError
+	}
+	return nil
+}
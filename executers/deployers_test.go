@@ -0,0 +1,59 @@
+package executers
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/params"
+	"github.com/jfrog/gocmd/tests/artifactorytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishToDeployers(t *testing.T) {
+	primary := createArtifactoryTestServer(t)
+	defer primary.Close()
+	dr := createArtifactoryTestServer(t)
+	defer dr.Close()
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	packages, err := GetDependencies(cachePath, map[string]bool{"rsc.io/quote@v1.5.2": true})
+	assert.NoError(t, err)
+	primaryManager, err := primary.ServicesManager()
+	assert.NoError(t, err)
+	drManager, err := dr.ServicesManager()
+	assert.NoError(t, err)
+
+	resolverDeployer := &params.ResolverDeployer{}
+	resolverDeployer.SetDeployer((&params.Params{}).SetRepo("go-local").SetServiceManager(primaryManager))
+	resolverDeployer.AddDeployer((&params.Params{}).SetRepo("go-local").SetServiceManager(drManager))
+	assert.Len(t, resolverDeployer.Deployers(), 2)
+	assert.Equal(t, primaryManager, resolverDeployer.Deployer().ServiceManager())
+	primaryTarget := GetDeployerTargetName(resolverDeployer.Deployers()[0])
+	drTarget := GetDeployerTargetName(resolverDeployer.Deployers()[1])
+
+	dependenciesCache := &cache.DependenciesCache{}
+	assert.NoError(t, PublishToDeployers(packages, resolverDeployer, dependenciesCache))
+	assert.NotEmpty(t, primary.Uploads())
+	assert.NotEmpty(t, dr.Uploads())
+	assert.Equal(t, []string{primaryTarget, drTarget}, dependenciesCache.GetTargetNames())
+	report := dependenciesCache.GetReport()
+	assert.Equal(t, 2, report.Published)
+	assert.Equal(t, 1, report.Targets[drTarget].Published)
+
+	// A failure of one deployer doesn't prevent publishing to the others, and is reported per deployer.
+	dr.SetCredentials(artifactorytest.DefaultUser, "wrong")
+	dependenciesCache = &cache.DependenciesCache{}
+	err = PublishToDeployers(packages, resolverDeployer, dependenciesCache)
+	var partialErr *PartialPublishError
+	if assert.True(t, errors.As(err, &partialErr)) {
+		assert.Equal(t, []string{primaryTarget}, partialErr.Succeeded)
+		assert.Contains(t, partialErr.Errors[drTarget], "rsc.io/quote:v1.5.2")
+	}
+	assert.Equal(t, 1, dependenciesCache.GetTarget(primaryTarget).GetReport().Published)
+	assert.Equal(t, 1, dependenciesCache.GetTarget(drTarget).GetReport().Failed)
+
+	// At least one deployer is required.
+	assert.Error(t, PublishToDeployers(packages, &params.ResolverDeployer{}, nil))
+}
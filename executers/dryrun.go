@@ -0,0 +1,131 @@
+package executers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+type DryRunOutcome string
+
+const (
+	// The package would be uploaded.
+	DryRunUpload DryRunOutcome = "upload"
+	// The package would be skipped, since it already exists in the target repository, or was already published.
+	DryRunSkip DryRunOutcome = "skip"
+	// The package would not be uploaded, since it fails a dependencies check, such as a Policy, or some of its files are missing.
+	DryRunReject DryRunOutcome = "reject"
+)
+
+// A file which would be uploaded, with the checksums sent to Artifactory.
+type DryRunFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	Sha1 string `json:"sha1"`
+	Md5  string `json:"md5"`
+}
+
+// The result of a dry run of publishing a package.
+type DryRunResult struct {
+	Id      string        `json:"id"`
+	Path    string        `json:"path"`
+	Version string        `json:"version"`
+	Outcome DryRunOutcome `json:"outcome"`
+	// The reason the package would be skipped or rejected.
+	Reason string       `json:"reason,omitempty"`
+	Files  []DryRunFile `json:"files,omitempty"`
+	// The total size of the files.
+	Size int64 `json:"size"`
+}
+
+// Performs all the steps of publishing the package to the target repository, except for the upload itself.
+// The files are located and their checksums are calculated, the dependencies checks, such as a Policy, are evaluated if set, and the target repository is checked for the package.
+func (dependencyPackage *Package) DryRun(targetRepo string, cache *cache.DependenciesCache, proxyClient *utils.ProxyClient) (*DryRunResult, error) {
+	result := dependencyPackage.newDryRunResult()
+	if cache.GetMap()[dependencyPackage.id] {
+		result.Outcome, result.Reason = DryRunSkip, "already published"
+		return result, nil
+	}
+	if dependencyPackage.zipPath == "" {
+		result.Outcome, result.Reason = DryRunReject, "no module zip to publish"
+		return result, nil
+	}
+	for _, path := range []string{dependencyPackage.zipPath, dependencyPackage.modPath, dependencyPackage.infoPath} {
+		if path == "" {
+			continue
+		}
+		if !fileutils.IsPathExists(path, false) {
+			result.Outcome, result.Reason = DryRunReject, "missing file: "+path
+			return result, nil
+		}
+		details, err := fileutils.GetFileDetails(path, true)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, DryRunFile{Path: path, Size: details.Size, Sha1: details.Checksum.Sha1, Md5: details.Checksum.Md5})
+		result.Size += details.Size
+	}
+	// Publishing fails if any of the checks fails, so each failure is a rejection.
+	if err := dependencyPackage.runDependenciesChecks(); err != nil {
+		result.Outcome, result.Reason = DryRunReject, err.Error()
+		return result, nil
+	}
+	exists, err := proxyClient.Exists(result.Path, result.Version)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		result.Outcome, result.Reason = DryRunSkip, "already exists in "+targetRepo
+		return result, nil
+	}
+	result.Outcome = DryRunUpload
+	return result, nil
+}
+
+func (dependencyPackage *Package) newDryRunResult() *DryRunResult {
+	idParts := strings.Split(dependencyPackage.id, ":")
+	return &DryRunResult{Id: dependencyPackage.id, Path: goModDecode(idParts[0]), Version: goModDecode(dependencyPackage.version)}
+}
+
+// Runs DryRun for each of the packages, and returns the results in the order of the packages.
+// Packages which appear more than once are reported as uploaded once, and then as skipped. The cache and the packages are not modified.
+// The dependencies checks of the options are evaluated in addition to the checks of each package, as when publishing.
+func DryRunPublish(packages []Package, targetRepo string, cache *cache.DependenciesCache, serviceManager artifactory.ArtifactoryServicesManager, options ...utils.Option) ([]DryRunResult, error) {
+	opts := utils.NewOptions(options...)
+	logger := opts.GetLogger()
+	proxyClient, err := utils.NewProxyClient(serviceManager.GetConfig().GetServiceDetails(), targetRepo)
+	if err != nil {
+		return nil, err
+	}
+	var results []DryRunResult
+	var uploadSize int64
+	uploads := map[string]bool{}
+	for i := range packages {
+		var result *DryRunResult
+		if uploads[packages[i].GetId()] {
+			result = packages[i].newDryRunResult()
+			result.Outcome, result.Reason = DryRunSkip, "already uploaded"
+		} else {
+			// The checks are copied, so the checks of the options aren't added to the package of the caller.
+			dependencyPackage := packages[i]
+			dependencyPackage.checks = append([]utils.DependenciesCheck{}, dependencyPackage.checks...)
+			dependencyPackage.setOptions(opts)
+			result, err = dependencyPackage.DryRun(targetRepo, cache, proxyClient)
+			if err != nil {
+				return nil, err
+			}
+		}
+		logger.Info(fmt.Sprintf("[Dry run] %s: %s %s", result.Outcome, result.Id, result.Reason))
+		if result.Outcome == DryRunUpload {
+			uploads[result.Id] = true
+			uploadSize += result.Size
+		}
+		results = append(results, *result)
+	}
+	logger.Info(fmt.Sprintf("[Dry run] %d out of %d packages would be uploaded to %s, with a total size of %d bytes", len(uploads), len(packages), targetRepo, uploadSize))
+	return results, nil
+}
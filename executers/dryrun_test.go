@@ -0,0 +1,95 @@
+package executers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRunPublish(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	createTestModuleZip(t, cachePath, "github.com/jfrog/lib", "v1.0.0", map[string]string{"go.mod": "module github.com/jfrog/lib\n"})
+	createTestModuleZip(t, cachePath, "github.com/jfrog/exists", "v1.0.0", map[string]string{"go.mod": "module github.com/jfrog/exists\n"})
+	createTestModuleZip(t, cachePath, "github.com/jfrog/denied", "v1.0.0", map[string]string{"go.mod": "module github.com/jfrog/denied\n"})
+	for _, name := range []string{"lib", "exists", "denied"} {
+		versionDir := filepath.Join(cachePath, "github.com", "jfrog", name, "@v")
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(versionDir, "v1.0.0.mod"), []byte("module github.com/jfrog/"+name+"\n"), 0644))
+		// The info file of lib is missing.
+		if name != "lib" {
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(versionDir, "v1.0.0.info"), []byte(`{"Version":"v1.0.0"}`), 0644))
+		}
+	}
+	assert.NoError(t, server.AddModule("go-local", "github.com/jfrog/exists", "v1.0.0", map[string][]byte{".mod": []byte("module github.com/jfrog/exists\n")}))
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+
+	var packages []Package
+	for _, dependency := range []string{"rsc.io/quote@v1.5.2", "github.com/jfrog/lib@v1.0.0", "github.com/jfrog/exists@v1.0.0", "github.com/jfrog/denied@v1.0.0", "rsc.io/quote@v1.5.2"} {
+		parts := strings.Split(dependency, "@")
+		dep, err := createDependency(cachePath, parts[0], parts[1], utils.NewOptions())
+		if !assert.NoError(t, err) {
+			return
+		}
+		packages = append(packages, *dep)
+	}
+	policy, err := createTestPolicy(t, `{"deny": [{"path": "github.com/jfrog/denied"}]}`)
+	assert.NoError(t, err)
+	packages[3].AddDependenciesCheck(policy)
+	dependenciesCache := &cache.DependenciesCache{}
+
+	results, err := DryRunPublish(packages, "go-local", dependenciesCache, servicesManager)
+	if !assert.NoError(t, err) || !assert.Len(t, results, 5) {
+		return
+	}
+	assert.Equal(t, DryRunUpload, results[0].Outcome)
+	assert.Equal(t, "rsc.io/quote", results[0].Path)
+	assert.Len(t, results[0].Files, 3)
+	var size int64
+	for _, file := range results[0].Files {
+		assert.NotEmpty(t, file.Sha1)
+		size += file.Size
+	}
+	assert.Equal(t, size, results[0].Size)
+	assert.Equal(t, DryRunReject, results[1].Outcome)
+	assert.Contains(t, results[1].Reason, "missing file")
+	assert.Equal(t, DryRunSkip, results[2].Outcome)
+	assert.Equal(t, "already exists in go-local", results[2].Reason)
+	assert.Equal(t, DryRunReject, results[3].Outcome)
+	assert.Contains(t, results[3].Reason, "denied by github.com/jfrog/denied")
+	assert.Equal(t, DryRunSkip, results[4].Outcome)
+	assert.Equal(t, "already uploaded", results[4].Reason)
+
+	// Nothing was uploaded, and the cache was not modified.
+	assert.Empty(t, server.Uploads())
+	for _, request := range server.Requests() {
+		assert.False(t, strings.HasPrefix(request, "PUT "), request)
+	}
+	assert.Empty(t, dependenciesCache.GetMap())
+
+	// The dependencies checks of the options reject the package, without being added to it.
+	existsPolicy, err := createTestPolicy(t, `{"deny": [{"path": "github.com/jfrog/exists"}]}`)
+	assert.NoError(t, err)
+	results, err = DryRunPublish(packages[2:3], "go-local", dependenciesCache, servicesManager, utils.WithDependenciesCheck(existsPolicy))
+	if assert.NoError(t, err) && assert.Len(t, results, 1) {
+		assert.Equal(t, DryRunReject, results[0].Outcome)
+		assert.Contains(t, results[0].Reason, "denied by github.com/jfrog/exists")
+	}
+	assert.Empty(t, packages[2].checks)
+
+	// A package without a module zip can't be published.
+	noZip := Package{id: "github.com/jfrog/nozip:v1.0.0", version: "v1.0.0"}
+	results, err = DryRunPublish([]Package{noZip}, "go-local", dependenciesCache, servicesManager)
+	if assert.NoError(t, err) && assert.Len(t, results, 1) {
+		assert.Equal(t, DryRunReject, results[0].Outcome)
+		assert.Equal(t, "no module zip to publish", results[0].Reason)
+	}
+}
@@ -0,0 +1,214 @@
+package executers
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"golang.org/x/mod/module"
+)
+
+// The source a dependency was downloaded from, as returned by DownloadDependenciesWithFallback.
+type ModuleResolution struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// Empty if the dependency couldn't be downloaded.
+	Source utils.Source `json:"source,omitempty"`
+	Error  string       `json:"error,omitempty"`
+	// The typed error of the failure, such as utils.ModuleNotFoundError or utils.BothSourcesError.
+	Err error `json:"-"`
+}
+
+// Returned by DownloadDependenciesWithFallback when some of the dependencies couldn't be downloaded from any source.
+type UnresolvedDependenciesError struct {
+	Resolutions []ModuleResolution
+}
+
+func (unresolvedError *UnresolvedDependenciesError) Error() string {
+	var messages []string
+	for _, resolution := range unresolvedError.Resolutions {
+		messages = append(messages, resolution.Error)
+	}
+	return fmt.Sprintf("%d dependencies couldn't be downloaded:\n%s", len(unresolvedError.Resolutions), strings.Join(messages, "\n"))
+}
+
+// Downloads the dependencies, which are in the format returned by cmd.GetDependenciesList, in phases:
+// First, from the Go repository in Artifactory only. Then, unless noFallback is set, directly from VCS for the dependencies which Artifactory failed to provide.
+// If server is nil, the dependencies are downloaded directly from VCS only.
+// Dependencies which already exist in the cache aren't downloaded, and their source is utils.CacheSource.
+// Note that the go command downloads the modules matched by GONOPROXY directly from VCS, even in the first phase.
+// The observer of the options receives the events of each downloaded dependency: ModuleStarted, ModuleRetried when it is tried from VCS
+// after Artifactory failed to provide it, and either ModuleFinished or ModuleFailed.
+// Returns the resolutions of all the dependencies, sorted by the module path and version.
+// An UnresolvedDependenciesError is also returned if some of the dependencies couldn't be downloaded.
+func DownloadDependenciesWithFallback(dependencies map[string]bool, cachePath string, server auth.ServiceDetails, repo string, noFallback bool, options ...utils.Option) ([]ModuleResolution, error) {
+	opts := utils.NewOptions(options...)
+	resolutions := map[string]*ModuleResolution{}
+	tries := map[string]*previousTries{}
+	var missing []string
+	for dependency := range dependencies {
+		modulePath, version := splitDependency(dependency)
+		if version == "" {
+			continue
+		}
+		resolutions[dependency] = &ModuleResolution{Path: modulePath, Version: version}
+		if isInCache(cachePath, modulePath, version) {
+			resolutions[dependency].Source = utils.CacheSource
+			continue
+		}
+		tries[dependency] = &previousTries{}
+		missing = append(missing, dependency)
+	}
+	sort.Strings(missing)
+	observer := opts.GetObserver()
+	for _, dependency := range missing {
+		observer.ModuleStarted(utils.ProgressEvent{Operation: utils.DownloadOperation, ModuleId: dependency, Repo: repo})
+	}
+
+	artifactoryErrors := map[string]error{}
+	if server != nil {
+		artifactoryUrl, err := utils.GetArtifactoryApiUrl(repo, server)
+		if err != nil {
+			return nil, err
+		}
+		artifactoryErrors, err = downloadPhase(missing, artifactoryUrl, true, resolutions, tries, nil, opts)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for _, dependency := range missing {
+			artifactoryErrors[dependency] = nil
+		}
+	}
+	if (!noFallback || server == nil) && len(artifactoryErrors) > 0 {
+		var failed []string
+		for _, dependency := range missing {
+			if _, ok := artifactoryErrors[dependency]; ok {
+				failed = append(failed, dependency)
+			}
+		}
+		opts.GetLogger().Info(fmt.Sprintf("Downloading %d dependencies directly from VCS.", len(failed)))
+		if server != nil {
+			for _, dependency := range failed {
+				observer.ModuleRetried(utils.ProgressEvent{Operation: utils.DownloadOperation, ModuleId: dependency, Attempt: 2, Err: artifactoryErrors[dependency]})
+			}
+		}
+		_, err := downloadPhase(failed, "direct", false, resolutions, tries, artifactoryErrors, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var results, unresolved []ModuleResolution
+	for _, resolution := range resolutions {
+		results = append(results, *resolution)
+		if resolution.Source == "" {
+			unresolved = append(unresolved, *resolution)
+		}
+	}
+	for _, dependency := range missing {
+		event := utils.ProgressEvent{Operation: utils.DownloadOperation, ModuleId: dependency}
+		if resolutions[dependency].Source == utils.ArtifactorySource {
+			event.Repo = repo
+		}
+		utils.NotifyFinished(observer, event, resolutions[dependency].Err)
+	}
+	sortResolutions(results)
+	if len(unresolved) > 0 {
+		sortResolutions(unresolved)
+		return results, &UnresolvedDependenciesError{Resolutions: unresolved}
+	}
+	return results, nil
+}
+
+// Downloads the dependencies using the provided GOPROXY, and updates their resolutions.
+// Returns the errors of the dependencies which failed to download in this phase.
+// A dependency which is missing from the output of the go command is failed with an explicit error.
+func downloadPhase(dependencies []string, goProxy string, usedProxy bool, resolutions map[string]*ModuleResolution, tries map[string]*previousTries, artifactoryErrors map[string]error, opts *utils.Options) (map[string]error, error) {
+	logger := opts.GetLogger()
+	downloaded, err := cmd.DownloadModules(dependencies, map[string]string{utils.GOPROXY: goProxy}, utils.WithLogger(logger))
+	if err != nil {
+		return nil, err
+	}
+	source := utils.VcsSource
+	if usedProxy {
+		source = utils.ArtifactorySource
+	}
+	reported := map[string]bool{}
+	for _, result := range downloaded {
+		reported[result.Path+"@"+result.Version] = true
+	}
+	for _, dependency := range dependencies {
+		if !reported[dependency] {
+			modulePath, version := splitDependency(dependency)
+			err = errorutils.CheckError(fmt.Errorf("%s is missing from the output of 'go mod download -json'", dependency))
+			downloaded = append(downloaded, cmd.DownloadedModule{Path: modulePath, Version: version, Error: err.Error(), Err: err})
+		}
+	}
+	phaseErrors := map[string]error{}
+	for _, result := range downloaded {
+		dependency := result.Path + "@" + result.Version
+		resolution, ok := resolutions[dependency]
+		if !ok {
+			continue
+		}
+		tries[dependency].setTriedFrom(usedProxy)
+		if result.Err == nil {
+			resolution.Source, resolution.Error, resolution.Err = source, "", nil
+			if !usedProxy {
+				logger.Info(fmt.Sprintf("%s was downloaded directly from VCS.", dependency))
+			}
+			continue
+		}
+		phaseErrors[dependency] = result.Err
+		if usedProxy {
+			resolution.Err = result.Err
+		} else {
+			resolution.Err = tries[dependency].newError(result.Path, result.Version, artifactoryErrors[dependency], result.Err)
+		}
+		resolution.Error = resolution.Err.Error()
+		logger.Debug(fmt.Sprintf("Failed downloading %s from %s: %s", dependency, source, result.Error))
+	}
+	return phaseErrors, nil
+}
+
+// Returns true if the zip of the module version exists in the cache.
+func isInCache(cachePath, modulePath, version string) bool {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return false
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return false
+	}
+	exists, err := fileutils.IsFileExists(filepath.Join(cachePath, filepath.FromSlash(escapedPath), "@v", escapedVersion+".zip"), false)
+	return err == nil && exists
+}
+
+// Returns the resolutions of the provided source.
+// For example, use utils.VcsSource to find the dependencies which bypassed Artifactory.
+func FilterResolutions(resolutions []ModuleResolution, source utils.Source) []ModuleResolution {
+	var filtered []ModuleResolution
+	for _, resolution := range resolutions {
+		if resolution.Source == source {
+			filtered = append(filtered, resolution)
+		}
+	}
+	return filtered
+}
+
+func sortResolutions(resolutions []ModuleResolution) {
+	sort.Slice(resolutions, func(i, j int) bool {
+		if resolutions[i].Path != resolutions[j].Path {
+			return resolutions[i].Path < resolutions[j].Path
+		}
+		return resolutions[i].Version < resolutions[j].Version
+	})
+}
@@ -0,0 +1,83 @@
+package executers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadDependenciesWithFallback(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	// The go command refuses to send credentials to an http server.
+	server.SetCredentials("", "")
+	createTestModuleZip(t, server.RepoDir("go-local"), "example.com/hello", "v1.0.0", map[string]string{"go.mod": "module example.com/hello\n", "hello.go": "package hello\n"})
+
+	tempDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer fileutils.RemoveTempDir(tempDir)
+	modCache := filepath.Join(tempDir, "modcache")
+	for key, value := range map[string]string{"GOMODCACHE": modCache, "GONOSUMDB": "example.com,example.invalid", "GOFLAGS": "-modcacherw"} {
+		defer os.Setenv(key, os.Getenv(key))
+		assert.NoError(t, os.Setenv(key, value))
+	}
+	cachePath := filepath.Join(modCache, "cache", "download")
+	createTestModuleZip(t, cachePath, "example.com/cached", "v1.0.0", map[string]string{"go.mod": "module example.com/cached\n"})
+	dependencies := map[string]bool{"example.com/hello@v1.0.0": true, "example.com/cached@v1.0.0": true, "example.invalid/missing@v1.0.0": true, "github.com/jfrog/gocmd@": true}
+
+	// Without fallback, the missing module isn't downloaded from VCS.
+	observer := &recordingObserver{}
+	resolutions, err := DownloadDependenciesWithFallback(dependencies, cachePath, server.ServiceDetails(), "go-local", true, utils.WithObserver(observer))
+	var unresolvedErr *UnresolvedDependenciesError
+	if assert.True(t, errors.As(err, &unresolvedErr)) {
+		assert.Len(t, unresolvedErr.Resolutions, 1)
+	}
+	if assert.Len(t, resolutions, 3) {
+		assert.Equal(t, ModuleResolution{Path: "example.com/cached", Version: "v1.0.0", Source: utils.CacheSource}, resolutions[0])
+		assert.Equal(t, ModuleResolution{Path: "example.com/hello", Version: "v1.0.0", Source: utils.ArtifactorySource}, resolutions[1])
+		assert.Equal(t, "example.invalid/missing", resolutions[2].Path)
+		assert.Empty(t, resolutions[2].Source)
+		var notFoundErr *utils.ModuleNotFoundError
+		assert.True(t, errors.As(resolutions[2].Err, &notFoundErr), resolutions[2].Err)
+	}
+	assert.FileExists(t, filepath.Join(cachePath, "example.com", "hello", "@v", "v1.0.0.zip"))
+	assert.Len(t, FilterResolutions(resolutions, utils.ArtifactorySource), 1)
+	assert.Equal(t, []string{
+		"started download example.com/hello@v1.0.0",
+		"started download example.invalid/missing@v1.0.0",
+		"finished download example.com/hello@v1.0.0",
+		"failed download example.invalid/missing@v1.0.0",
+	}, observer.events)
+
+	// With fallback, the module is tried from VCS too, after it was downloaded from Artifactory.
+	observer.events = nil
+	resolutions, err = DownloadDependenciesWithFallback(dependencies, cachePath, server.ServiceDetails(), "go-local", false, utils.WithObserver(observer))
+	assert.Error(t, err)
+	assert.Equal(t, []string{
+		"started download example.invalid/missing@v1.0.0",
+		"retried download example.invalid/missing@v1.0.0",
+		"failed download example.invalid/missing@v1.0.0",
+	}, observer.events)
+	if assert.Len(t, resolutions, 3) {
+		assert.Equal(t, utils.CacheSource, resolutions[1].Source)
+		var bothErr *utils.BothSourcesError
+		if assert.True(t, errors.As(resolutions[2].Err, &bothErr), resolutions[2].Err) {
+			var notFoundErr *utils.ModuleNotFoundError
+			assert.True(t, errors.As(bothErr.ArtifactoryErr, &notFoundErr))
+			assert.Error(t, bothErr.VcsErr)
+		}
+	}
+
+	// A dependency which is missing from the output of the go command, since it isn't a canonical version, gets an explicit error.
+	resolutions, err = DownloadDependenciesWithFallback(map[string]bool{"example.com/hello@v1.0": true}, cachePath, server.ServiceDetails(), "go-local", true)
+	assert.Error(t, err)
+	if assert.Len(t, resolutions, 1) {
+		assert.Empty(t, resolutions[0].Source)
+		assert.Contains(t, resolutions[0].Error, "missing from the output")
+	}
+}
@@ -1,12 +1,23 @@
 package executers
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
 	buildinfo "github.com/jfrog/build-info-go/entities"
 
 	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	_go "github.com/jfrog/jfrog-client-go/artifactory/services/go"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
@@ -18,6 +29,9 @@ type GoPackage interface {
 	New(cachePath string, dependency Package) GoPackage
 }
 
+// Matches the errors returned by the jfrog-client-go for unexpected responses.
+var serverResponseRegExp = regexp.MustCompile(`^Server response: (\d{3})`)
+
 // Represent go dependency package.
 type Package struct {
 	buildInfoDependencies []buildinfo.Dependency
@@ -27,6 +41,9 @@ type Package struct {
 	modPath               string
 	infoPath              string
 	version               string
+	checks                []utils.DependenciesCheck
+	observer              utils.Observer
+	logger                log.Log
 }
 
 func (dependencyPackage *Package) New(cachePath string, dep Package) GoPackage {
@@ -37,6 +54,9 @@ func (dependencyPackage *Package) New(cachePath string, dep Package) GoPackage {
 	dependencyPackage.buildInfoDependencies = dep.buildInfoDependencies
 	dependencyPackage.modPath = dep.modPath
 	dependencyPackage.infoPath = dep.infoPath
+	dependencyPackage.checks = dep.checks
+	dependencyPackage.observer = dep.observer
+	dependencyPackage.logger = dep.logger
 	return dependencyPackage
 }
 
@@ -56,6 +76,11 @@ func (dependencyPackage *Package) GetZipPath() string {
 	return dependencyPackage.zipPath
 }
 
+// Adds a check which runs on the package before it is published. Publishing fails if the check fails.
+func (dependencyPackage *Package) AddDependenciesCheck(check utils.DependenciesCheck) {
+	dependencyPackage.checks = append(dependencyPackage.checks, check)
+}
+
 // Init the dependency information if needed.
 func (dependencyPackage *Package) Init() error {
 	return nil
@@ -66,7 +91,8 @@ func (dependencyPackage *Package) PopulateModAndPublish(targetRepo string, cache
 	if !published {
 		return dependencyPackage.prepareAndPublish(targetRepo, cache, serviceManager)
 	} else {
-		log.Debug(fmt.Sprintf("Dependency %s was published previosly to Artifactory", dependencyPackage.GetId()))
+		dependencyPackage.getLogger().Debug(fmt.Sprintf("Dependency %s was published previosly to Artifactory", dependencyPackage.GetId()))
+		cache.AddSkipped(dependencyPackage.GetId(), dependencyPackage.getTargetUrl(targetRepo, serviceManager))
 	}
 	return nil
 }
@@ -74,21 +100,64 @@ func (dependencyPackage *Package) PopulateModAndPublish(targetRepo string, cache
 // Prepare for publishing and publish the dependency to Artifactory
 func (dependencyPackage *Package) prepareAndPublish(targetRepo string, cache *cache.DependenciesCache, serviceManager artifactory.ArtifactoryServicesManager) error {
 	successOutOfTotal := fmt.Sprintf("%d/%d", cache.GetSuccesses()+1, cache.GetTotal())
+	targetUrl := dependencyPackage.getTargetUrl(targetRepo, serviceManager)
+	start := time.Now()
 	err := dependencyPackage.Publish(successOutOfTotal, targetRepo, serviceManager)
 	if err != nil {
 		cache.IncrementFailures()
+		cache.AddFailed(dependencyPackage.GetId(), targetUrl, err, time.Since(start))
 		return err
 	}
 	cache.IncrementSuccess()
+	cache.AddPublished(dependencyPackage.GetId(), targetUrl, dependencyPackage.getFilesSize(), time.Since(start))
 	return nil
 }
 
+// Returns the URL of the package version in the target repository, without the file extension.
+func (dependencyPackage *Package) getTargetUrl(targetRepo string, serviceManager artifactory.ArtifactoryServicesManager) string {
+	if serviceManager == nil || serviceManager.GetConfig() == nil {
+		return ""
+	}
+	moduleId := strings.Split(dependencyPackage.id, ":")[0]
+	return clientutils.AddTrailingSlashIfNeeded(serviceManager.GetConfig().GetServiceDetails().GetUrl()) + "api/go/" + targetRepo + "/" + moduleId + "/@v/" + dependencyPackage.version
+}
+
+// Returns the total size of the files uploaded when publishing the package.
+func (dependencyPackage *Package) getFilesSize() int64 {
+	var size int64
+	for _, path := range []string{dependencyPackage.zipPath, dependencyPackage.modPath, dependencyPackage.infoPath} {
+		if stat, err := os.Stat(path); path != "" && err == nil {
+			size += stat.Size()
+		}
+	}
+	return size
+}
+
 func (dependencyPackage *Package) Publish(summary string, targetRepo string, servicesManager artifactory.ArtifactoryServicesManager) error {
-	message := fmt.Sprintf("Publishing: %s to %s", dependencyPackage.id, targetRepo)
-	if summary != "" {
-		message += ":" + summary
+	observer := dependencyPackage.getObserver()
+	event := utils.ProgressEvent{Operation: utils.PublishOperation, ModuleId: dependencyPackage.id, Repo: targetRepo, Progress: summary}
+	observer.ModuleStarted(event)
+	err := dependencyPackage.publish(targetRepo, servicesManager)
+	if err != nil {
+		event.Err = err
+		observer.ModuleFailed(event)
+		return err
+	}
+	// jfrog-client-go doesn't report the progress of the upload, so the uploaded bytes are reported once the files are uploaded.
+	event.Bytes = dependencyPackage.getFilesSize()
+	observer.BytesTransferred(event)
+	observer.ModuleFinished(event)
+	return nil
+}
+
+func (dependencyPackage *Package) publish(targetRepo string, servicesManager artifactory.ArtifactoryServicesManager) error {
+	err := dependencyPackage.runDependenciesChecks()
+	if err != nil {
+		return err
+	}
+	if dependencyPackage.zipPath == "" {
+		return errorutils.CheckError(errors.New(fmt.Sprintf("%s has no module zip to publish", dependencyPackage.id)))
 	}
-	log.Info(message)
 	params := _go.NewGoParams()
 	params.ZipPath = dependencyPackage.zipPath
 	params.ModContent = dependencyPackage.modContent
@@ -97,10 +166,81 @@ func (dependencyPackage *Package) Publish(summary string, targetRepo string, ser
 	params.ModuleId = dependencyPackage.id
 	params.ModPath = dependencyPackage.modPath
 	params.InfoPath = dependencyPackage.infoPath
-	_, err := servicesManager.PublishGoProject(params)
+	_, err = servicesManager.PublishGoProject(params)
+	if err != nil {
+		return dependencyPackage.publishError(err, dependencyPackage.getTargetUrl(targetRepo, servicesManager))
+	}
+	return nil
+}
+
+// Converts an error returned by Artifactory while publishing the package to a typed error, if its status has one.
+func (dependencyPackage *Package) publishError(err error, targetUrl string) error {
+	match := serverResponseRegExp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	statusCode, _ := strconv.Atoi(match[1])
+	modulePath := goModDecode(strings.Split(dependencyPackage.id, ":")[0])
+	version := goModDecode(dependencyPackage.version)
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return &utils.AuthenticationError{Module: modulePath, Version: version, Url: targetUrl, StatusCode: statusCode}
+	case statusCode == http.StatusConflict && strings.Contains(strings.ToLower(err.Error()), "checksum"):
+		return &utils.ChecksumMismatchError{Module: modulePath, Version: version, File: targetUrl}
+	}
 	return err
 }
 
+// Sets an observer which receives the progress of publishing the package, instead of the global observer.
+func (dependencyPackage *Package) SetObserver(observer utils.Observer) {
+	dependencyPackage.observer = observer
+}
+
+// If a logger is set and an observer isn't, the progress is logged by a utils.LogObserver using the logger.
+func (dependencyPackage *Package) getObserver() utils.Observer {
+	if dependencyPackage.observer != nil {
+		return dependencyPackage.observer
+	}
+	if dependencyPackage.logger != nil {
+		return &utils.LogObserver{Logger: dependencyPackage.getLogger()}
+	}
+	return utils.GetObserver()
+}
+
+// Sets the logger and the observer of the options, if they are set, on the package, and adds the dependencies checks of the options.
+func (dependencyPackage *Package) setOptions(opts *utils.Options) {
+	dependencyPackage.checks = append(dependencyPackage.checks, opts.DependenciesChecks...)
+	if opts.Logger != nil {
+		dependencyPackage.logger = opts.Logger
+	}
+	if opts.Observer != nil {
+		dependencyPackage.observer = opts.Observer
+	}
+}
+
+// Sets the logger used for the package, instead of the global logger. The package id is added to the fields of the logger.
+func (dependencyPackage *Package) SetLogger(logger log.Log) {
+	dependencyPackage.logger = logger
+}
+
+func (dependencyPackage *Package) getLogger() log.Log {
+	if dependencyPackage.logger != nil {
+		return utils.WithLogFields(dependencyPackage.logger, utils.LogFields{"module": dependencyPackage.id})
+	}
+	return utils.GetGlobalLogger()
+}
+
+// Runs the dependencies checks on the module version of the package.
+func (dependencyPackage *Package) runDependenciesChecks() error {
+	dependency := goModDecode(strings.Split(dependencyPackage.id, ":")[0]) + "@" + goModDecode(dependencyPackage.version)
+	for _, check := range dependencyPackage.checks {
+		if err := check.CheckDependencies(map[string]bool{dependency: true}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (dependencyPackage *Package) Dependencies() []buildinfo.Dependency {
 	return dependencyPackage.buildInfoDependencies
 }
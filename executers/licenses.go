@@ -0,0 +1,241 @@
+package executers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// The SPDX identifier used when a license file was found, but could not be classified.
+	UnknownLicense = "NOASSERTION"
+	// The prefix of the build-info module properties added by AddLicensesProperties.
+	LicensesPropertyPrefix = "go.licenses."
+	// The minimal confidence for classifying a license file.
+	minLicenseConfidence = 0.5
+)
+
+// A license file found in the root directory of a module.
+type LicenseFile struct {
+	// The path of the file, relative to the module root.
+	File   string `json:"file"`
+	SpdxId string `json:"spdxId"`
+	// The share of the distinguishing phrases of the license which were found in the file, between 0 and 1.
+	Confidence float64 `json:"confidence"`
+}
+
+// The licenses of a dependency.
+type ModuleLicenses struct {
+	Path     string        `json:"path"`
+	Version  string        `json:"version"`
+	Licenses []LicenseFile `json:"licenses,omitempty"`
+}
+
+// Returns an SPDX license expression combining the licenses found in the module, or NOASSERTION if no license was found.
+func (moduleLicenses *ModuleLicenses) SpdxExpression() string {
+	var ids []string
+	exists := map[string]bool{}
+	for _, license := range moduleLicenses.Licenses {
+		if !exists[license.SpdxId] {
+			exists[license.SpdxId] = true
+			ids = append(ids, license.SpdxId)
+		}
+	}
+	if len(ids) == 0 {
+		return UnknownLicense
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, " AND ")
+}
+
+// The phrases which identify a license in a normalized license text.
+// A license is not matched if one of its excluding phrases is found, so similar licenses can be told apart.
+type licensePattern struct {
+	spdxId    string
+	phrases   []string
+	excluding []string
+}
+
+// The GNU licenses are identified as the -only variants, since the license text alone doesn't grant the later versions.
+var licensePatterns = []licensePattern{
+	{spdxId: "MIT", phrases: []string{
+		"permission is hereby granted free of charge to any person obtaining a copy",
+		"the above copyright notice and this permission notice shall be included in all copies or substantial portions of the software",
+		"the software is provided as is without warranty of any kind",
+	}},
+	{spdxId: "Apache-2.0", phrases: []string{
+		"apache license version 2 0",
+		"terms and conditions for use reproduction and distribution",
+		"grant of patent license",
+		"licensed under the apache license version 2 0",
+	}},
+	{spdxId: "BSD-3-Clause", phrases: []string{
+		"redistribution and use in source and binary forms with or without modification are permitted",
+		"redistributions of source code must retain the above copyright notice",
+		"redistributions in binary form must reproduce the above copyright notice",
+		"may be used to endorse or promote products derived from this software without specific prior written permission",
+	}},
+	{spdxId: "BSD-2-Clause", phrases: []string{
+		"redistribution and use in source and binary forms with or without modification are permitted",
+		"redistributions of source code must retain the above copyright notice",
+		"redistributions in binary form must reproduce the above copyright notice",
+	}, excluding: []string{"endorse or promote products derived from this software"}},
+	{spdxId: "ISC", phrases: []string{
+		"permission to use copy modify and or distribute this software for any purpose with or without fee is hereby granted",
+		"the software is provided as is and the author disclaims all warranties",
+	}},
+	{spdxId: "MPL-2.0", phrases: []string{
+		"mozilla public license version 2 0",
+		"this source code form is subject to the terms of the mozilla public license",
+	}},
+	{spdxId: "GPL-2.0-only", phrases: []string{"gnu general public license version 2 june 1991"}},
+	{spdxId: "GPL-3.0-only", phrases: []string{"gnu general public license version 3 29 june 2007"}},
+	{spdxId: "LGPL-2.1-only", phrases: []string{"gnu lesser general public license version 2 1 february 1999"}},
+	{spdxId: "LGPL-3.0-only", phrases: []string{"gnu lesser general public license version 3 29 june 2007"}},
+	{spdxId: "AGPL-3.0-only", phrases: []string{"gnu affero general public license version 3 19 november 2007"}},
+	{spdxId: "Unlicense", phrases: []string{
+		"this is free and unencumbered software released into the public domain",
+		"for more information please refer to http unlicense org",
+	}},
+	{spdxId: "CC0-1.0", phrases: []string{"creative commons legal code", "cc0 1 0 universal"}},
+}
+
+var (
+	licenseFileRegExp       = regexp.MustCompile(`(?i)^(un)?licen[cs]e|^copying|^copyright|^notice`)
+	licenseNormalizeRegExp  = regexp.MustCompile(`[^a-z0-9]+`)
+	licenseWhitespaceRegExp = regexp.MustCompile(`\s+`)
+)
+
+// Returns the licenses of the dependencies, which are in the format returned by cmd.GetDependenciesList, sorted by the module path.
+// The licenses are detected in the zips of the dependencies in the Go cache. Dependencies without a zip in the cache are skipped.
+func GetLicenses(cachePath string, dependencies map[string]bool, options ...utils.Option) ([]ModuleLicenses, error) {
+	logger := utils.NewOptions(options...).GetLogger()
+	var results []ModuleLicenses
+	for dependency := range dependencies {
+		modulePath, version := splitDependency(dependency)
+		if version == "" {
+			continue
+		}
+		zipPath, err := getPackageZipLocation(cachePath, goModEncode(modulePath), goModEncode(version), logger)
+		if err != nil {
+			return nil, err
+		}
+		if zipPath == "" {
+			continue
+		}
+		licenses, err := detectZipLicenses(zipPath, modulePath, version, logger)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, ModuleLicenses{Path: modulePath, Version: version, Licenses: licenses})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+	return results, nil
+}
+
+// Unzips the module zip to a temp directory, and classifies the license files in the module root.
+func detectZipLicenses(zipPath, modulePath, version string, logger log.Log) (licenses []LicenseFile, err error) {
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		e := fileutils.RemoveTempDir(tempDir)
+		if err == nil {
+			err = e
+		}
+	}()
+	err = createDependencyInTemp(zipPath, tempDir)
+	if err != nil {
+		return nil, err
+	}
+	return DetectLicenses(filepath.Join(tempDir, filepath.FromSlash(modulePath+"@"+version)), utils.WithLogger(logger))
+}
+
+// Classifies the license files in the root of the module directory.
+func DetectLicenses(moduleDir string, options ...utils.Option) ([]LicenseFile, error) {
+	logger := utils.NewOptions(options...).GetLogger()
+	files, err := ioutil.ReadDir(moduleDir)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var licenses []LicenseFile
+	for _, file := range files {
+		if file.IsDir() || !licenseFileRegExp.MatchString(file.Name()) {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(moduleDir, file.Name()))
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		spdxId, confidence := ClassifyLicense(content)
+		logger.Debug(fmt.Sprintf("Classified %s as %s with confidence %.2f", filepath.Join(moduleDir, file.Name()), spdxId, confidence))
+		// Files such as NOTICE, which don't include a known license, are not reported.
+		if spdxId == UnknownLicense && !strings.HasPrefix(strings.ToLower(file.Name()), "licen") {
+			continue
+		}
+		licenses = append(licenses, LicenseFile{File: file.Name(), SpdxId: spdxId, Confidence: confidence})
+	}
+	return licenses, nil
+}
+
+// Returns the SPDX identifier of the license text, and the confidence of the classification.
+// If no license is matched with enough confidence, NOASSERTION is returned.
+func ClassifyLicense(content []byte) (spdxId string, confidence float64) {
+	text := normalizeLicenseText(string(content))
+	spdxId = UnknownLicense
+	matchedPhrases := 0
+	for _, pattern := range licensePatterns {
+		if containsAny(text, pattern.excluding) {
+			continue
+		}
+		matched := 0
+		for _, phrase := range pattern.phrases {
+			if strings.Contains(text, phrase) {
+				matched++
+			}
+		}
+		patternConfidence := float64(matched) / float64(len(pattern.phrases))
+		// When the confidence is equal, prefer the license which more of its phrases were found.
+		if patternConfidence >= minLicenseConfidence && (patternConfidence > confidence || (patternConfidence == confidence && matched > matchedPhrases)) {
+			spdxId, confidence, matchedPhrases = pattern.spdxId, patternConfidence, matched
+		}
+	}
+	return
+}
+
+// Lowercases the text, and replaces the punctuation and whitespace sequences by a single space.
+func normalizeLicenseText(text string) string {
+	text = licenseNormalizeRegExp.ReplaceAllString(strings.ToLower(text), " ")
+	return strings.TrimSpace(licenseWhitespaceRegExp.ReplaceAllString(text, " "))
+}
+
+func containsAny(text string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds a property to the build-info module for each dependency, in the format 'go.licenses.<path>@<version>=<SPDX expression>'.
+// Existing properties of the module are kept. An error is returned if they can't be merged with the added properties.
+func AddLicensesProperties(module *buildinfo.Module, licenses []ModuleLicenses) error {
+	properties := map[string]string{}
+	for i := range licenses {
+		properties[LicensesPropertyPrefix+licenses[i].Path+"@"+licenses[i].Version] = licenses[i].SpdxExpression()
+	}
+	return addModuleProperties(module, properties)
+}
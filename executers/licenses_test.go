@@ -0,0 +1,121 @@
+package executers
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mitLicense = `MIT License
+
+Copyright (c) 2020 JFrog
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction.
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED.
+`
+	bsd2License = `Copyright (c) 2020 JFrog. All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice,
+   this list of conditions and the following disclaimer.
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation.
+`
+	bsd3License = bsd2License + `3. Neither the name of the copyright holder nor the names of its contributors
+   may be used to endorse or promote products derived from this software
+   without specific prior written permission.
+`
+	apacheHeader = `Copyright 2020 JFrog
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+`
+)
+
+func TestClassifyLicense(t *testing.T) {
+	tests := []struct {
+		name               string
+		content            string
+		expectedId         string
+		expectedConfidence float64
+	}{
+		{"mit", mitLicense, "MIT", 1},
+		{"bsd2", bsd2License, "BSD-2-Clause", 1},
+		{"bsd3", bsd3License, "BSD-3-Clause", 1},
+		{"apacheHeader", apacheHeader, "Apache-2.0", 0.5},
+		{"unknown", "All rights reserved.", UnknownLicense, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spdxId, confidence := ClassifyLicense([]byte(test.content))
+			assert.Equal(t, test.expectedId, spdxId)
+			assert.Equal(t, test.expectedConfidence, confidence)
+		})
+	}
+}
+
+func TestGetLicenses(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	cachePath, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer fileutils.RemoveTempDir(cachePath)
+	createTestModuleZip(t, cachePath, "github.com/jfrog/lib", "v1.0.0", map[string]string{"LICENSE": mitLicense, "lib.go": "package lib\n"})
+	createTestModuleZip(t, cachePath, "github.com/Jfrog/Upper", "v1.2.0", map[string]string{
+		"COPYING":         bsd3License,
+		"NOTICE":          "This product includes software developed by JFrog.\n",
+		"LICENSE.unknown": "All rights reserved.\n",
+		"sub/LICENSE":     mitLicense,
+	})
+
+	dependencies := map[string]bool{
+		"github.com/jfrog/main@":        true,
+		"github.com/jfrog/lib@v1.0.0":   true,
+		"github.com/Jfrog/Upper@v1.2.0": true,
+		"github.com/jfrog/nozip@v1.0.0": true,
+	}
+	licenses, err := GetLicenses(cachePath, dependencies)
+	assert.NoError(t, err)
+	assert.Equal(t, []ModuleLicenses{
+		{Path: "github.com/Jfrog/Upper", Version: "v1.2.0", Licenses: []LicenseFile{
+			{File: "COPYING", SpdxId: "BSD-3-Clause", Confidence: 1},
+			{File: "LICENSE.unknown", SpdxId: UnknownLicense},
+		}},
+		{Path: "github.com/jfrog/lib", Version: "v1.0.0", Licenses: []LicenseFile{{File: "LICENSE", SpdxId: "MIT", Confidence: 1}}},
+	}, licenses)
+	assert.Equal(t, "BSD-3-Clause AND NOASSERTION", licenses[0].SpdxExpression())
+	assert.Equal(t, UnknownLicense, (&ModuleLicenses{}).SpdxExpression())
+}
+
+// Creates a module zip in the cache, which its files are under the '<module>@<version>/' prefix.
+func createTestModuleZip(t *testing.T, cachePath, modulePath, version string, files map[string]string) {
+	versionDir := filepath.Join(cachePath, goModEncode(modulePath), "@v")
+	assert.NoError(t, os.MkdirAll(versionDir, 0755))
+	zipFile, err := os.Create(filepath.Join(versionDir, version+".zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zipFile.Close()
+	zipWriter := zip.NewWriter(zipFile)
+	for name, content := range files {
+		writer, err := zipWriter.Create(modulePath + "@" + version + "/" + name)
+		assert.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zipWriter.Close())
+}
@@ -0,0 +1,247 @@
+package executers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"golang.org/x/mod/module"
+)
+
+type MirrorOutcome string
+
+const (
+	MirrorMirrored MirrorOutcome = "mirrored"
+	// The module version already exists in the target repository.
+	MirrorSkipped MirrorOutcome = "skipped"
+	// Only the go.mod hash of the version is recorded in go.sum, so its zip can't be verified, and the version isn't mirrored.
+	MirrorGoModOnly MirrorOutcome = "go.mod-only"
+	MirrorFailed    MirrorOutcome = "failed"
+)
+
+// The result of mirroring a module version, as returned by MirrorModules.
+type MirrorResult struct {
+	Path    string        `json:"path"`
+	Version string        `json:"version"`
+	Outcome MirrorOutcome `json:"outcome"`
+	// The h1 hashes of the mirrored files, as recorded in go.sum.
+	ZipHash   string `json:"zipHash,omitempty"`
+	GoModHash string `json:"goModHash,omitempty"`
+	Error     string `json:"error,omitempty"`
+	// The typed error of the failure, such as utils.ModuleNotFoundError or utils.ChecksumMismatchError.
+	Err error `json:"-"`
+}
+
+// Returned by MirrorModules when some of the modules couldn't be mirrored.
+type MirrorError struct {
+	Results []MirrorResult
+}
+
+func (mirrorError *MirrorError) Error() string {
+	var messages []string
+	for _, result := range mirrorError.Results {
+		messages = append(messages, fmt.Sprintf("%s@%s: %s", result.Path, result.Version, result.Error))
+	}
+	return fmt.Sprintf("%d modules couldn't be mirrored:\n%s", len(mirrorError.Results), strings.Join(messages, "\n"))
+}
+
+// Mirrors the dependencies, which are in the format returned by cmd.GetDependenciesList. See MirrorModules.
+func MirrorDependencies(dependencies map[string]bool, sourceDetails auth.ServiceDetails, sourceRepo, targetRepo string, serviceManager artifactory.ArtifactoryServicesManager, options ...utils.Option) ([]MirrorResult, error) {
+	var entries []utils.GoSumEntry
+	for dependency := range dependencies {
+		modulePath, version := splitDependency(dependency)
+		if version != "" {
+			entries = append(entries, utils.GoSumEntry{Path: modulePath, Version: version})
+		}
+	}
+	return MirrorModules(entries, sourceDetails, sourceRepo, targetRepo, serviceManager, options...)
+}
+
+// Mirrors the module versions listed in a go.sum file, and verifies their checksums against it. See MirrorModules.
+func MirrorGoSum(goSumPath string, sourceDetails auth.ServiceDetails, sourceRepo, targetRepo string, serviceManager artifactory.ArtifactoryServicesManager, options ...utils.Option) ([]MirrorResult, error) {
+	entries, err := utils.ReadGoSum(goSumPath)
+	if err != nil {
+		return nil, err
+	}
+	return MirrorModules(entries, sourceDetails, sourceRepo, targetRepo, serviceManager, options...)
+}
+
+// Copies module versions from a source Go repository to a target Go repository, for example to promote vetted modules.
+// The .info, .mod and .zip files are downloaded from the source using the GOPROXY protocol, and published to the target.
+// The .info file is published as is, so the timestamp of the version is preserved, and it is verified after publishing.
+// The checksums of the files are verified end to end: against the hashes of the entry if it has them,
+// and against the files which are downloaded back from the target after publishing.
+// Versions which already exist in the target are skipped. Entries which have only a go.mod hash aren't mirrored, since their zips can't be verified.
+// Returns a result per entry, in the order of the entries. A MirrorError is also returned if some of the modules failed.
+func MirrorModules(entries []utils.GoSumEntry, sourceDetails auth.ServiceDetails, sourceRepo, targetRepo string, serviceManager artifactory.ArtifactoryServicesManager, options ...utils.Option) ([]MirrorResult, error) {
+	opts := utils.NewOptions(options...)
+	logger := opts.GetLogger()
+	sourceClient, err := utils.NewProxyClient(sourceDetails, sourceRepo)
+	if err != nil {
+		return nil, err
+	}
+	targetClient, err := utils.NewProxyClient(serviceManager.GetConfig().GetServiceDetails(), targetRepo)
+	if err != nil {
+		return nil, err
+	}
+	sourceClient.SetObserver(opts.GetObserver())
+	targetClient.SetObserver(opts.GetObserver())
+	var results, failed []MirrorResult
+	for _, entry := range entries {
+		result := MirrorResult{Path: entry.Path, Version: entry.Version}
+		result.Outcome, err = mirrorModule(entry, sourceClient, targetClient, serviceManager, &result, opts)
+		if err != nil {
+			result.Outcome, result.Error, result.Err = MirrorFailed, err.Error(), err
+			logger.Error(fmt.Sprintf("Failed mirroring %s@%s: %s", entry.Path, entry.Version, err.Error()))
+			failed = append(failed, result)
+		} else {
+			logger.Info(fmt.Sprintf("%s@%s: %s", entry.Path, entry.Version, result.Outcome))
+		}
+		results = append(results, result)
+	}
+	if len(failed) > 0 {
+		return results, &MirrorError{Results: failed}
+	}
+	return results, nil
+}
+
+func mirrorModule(entry utils.GoSumEntry, sourceClient, targetClient *utils.ProxyClient, serviceManager artifactory.ArtifactoryServicesManager, result *MirrorResult, opts *utils.Options) (MirrorOutcome, error) {
+	if entry.IsGoModOnly() {
+		return MirrorGoModOnly, nil
+	}
+	exists, err := targetClient.Exists(entry.Path, entry.Version)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return MirrorSkipped, nil
+	}
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return "", err
+	}
+	defer fileutils.RemoveTempDir(tempDir)
+
+	dependencyPackage, err := downloadModuleFiles(entry.Path, entry.Version, sourceClient, filepath.Join(tempDir, "source"))
+	if err != nil {
+		return "", err
+	}
+	dependencyPackage.setOptions(opts)
+	result.ZipHash, result.GoModHash, err = hashModuleFiles(dependencyPackage)
+	if err != nil {
+		return "", err
+	}
+	err = verifyHashes(entry.Path, entry.Version, utils.ArtifactorySource, entry.ZipHash, entry.GoModHash, result.ZipHash, result.GoModHash)
+	if err != nil {
+		return "", err
+	}
+	err = dependencyPackage.Publish("", targetClient.GetRepo(), serviceManager)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify the files as they are served by the target.
+	published, err := downloadModuleFiles(entry.Path, entry.Version, targetClient, filepath.Join(tempDir, "target"))
+	if err != nil {
+		return "", err
+	}
+	zipHash, goModHash, err := hashModuleFiles(published)
+	if err != nil {
+		return "", err
+	}
+	err = verifyHashes(entry.Path, entry.Version, utils.ArtifactorySource, result.ZipHash, result.GoModHash, zipHash, goModHash)
+	if err != nil {
+		return "", err
+	}
+	return MirrorMirrored, verifyVersionTime(entry.Path, entry.Version, dependencyPackage.infoPath, published.infoPath)
+}
+
+// Returns an error if the time of the version in the published .info file differs from its time in the source .info file.
+func verifyVersionTime(modulePath, version, sourceInfoPath, targetInfoPath string) error {
+	sourceTime, err := getVersionTime(version, sourceInfoPath)
+	if err != nil {
+		return err
+	}
+	targetTime, err := getVersionTime(version, targetInfoPath)
+	if err != nil {
+		return err
+	}
+	if !sourceTime.Equal(targetTime) {
+		return errorutils.CheckError(fmt.Errorf("the time of %s@%s in the target is %s, while it is %s in the source", modulePath, version, targetTime.Format(time.RFC3339), sourceTime.Format(time.RFC3339)))
+	}
+	return nil
+}
+
+// Downloads the .info, .mod and .zip files of the module version into dir, and returns a package for publishing them.
+func downloadModuleFiles(modulePath, version string, proxyClient *utils.ProxyClient, dir string) (*Package, error) {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	dependencyPackage := &Package{
+		id:       escapedPath + ":" + escapedVersion,
+		version:  escapedVersion,
+		zipPath:  filepath.Join(dir, escapedVersion+".zip"),
+		modPath:  filepath.Join(dir, escapedVersion+".mod"),
+		infoPath: filepath.Join(dir, escapedVersion+".info"),
+	}
+	infoContent, err := proxyClient.InfoContent(modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(dependencyPackage.infoPath, infoContent, 0644); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	dependencyPackage.modContent, err = proxyClient.Mod(modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(dependencyPackage.modPath, dependencyPackage.modContent, 0644); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	zipFile, err := os.Create(dependencyPackage.zipPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer zipFile.Close()
+	_, err = proxyClient.Zip(modulePath, version, zipFile)
+	if err != nil {
+		return nil, err
+	}
+	return dependencyPackage, nil
+}
+
+func hashModuleFiles(dependencyPackage *Package) (zipHash, goModHash string, err error) {
+	zipHash, err = utils.HashZip(dependencyPackage.zipPath)
+	if err != nil {
+		return
+	}
+	goModHash, err = utils.HashGoMod(dependencyPackage.modContent)
+	return
+}
+
+// Returns a utils.ChecksumMismatchError if the actual hashes don't match the expected hashes. Empty expected hashes are not verified.
+func verifyHashes(modulePath, version string, source utils.Source, expectedZipHash, expectedGoModHash, zipHash, goModHash string) error {
+	if expectedZipHash != "" && expectedZipHash != zipHash {
+		return &utils.ChecksumMismatchError{Module: modulePath, Version: version, Source: source, File: "zip", Expected: expectedZipHash, Actual: zipHash}
+	}
+	if expectedGoModHash != "" && expectedGoModHash != goModHash {
+		return &utils.ChecksumMismatchError{Module: modulePath, Version: version, Source: source, File: "go.mod", Expected: expectedGoModHash, Actual: goModHash}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package executers
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testInfoContent = `{"Version":"v1.5.2","Time":"2018-02-14T15:44:20Z"}`
+
+func TestMirrorModules(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	assert.NoError(t, server.AddRepo("go-staging"))
+	zipContent, err := ioutil.ReadFile(filepath.Join("..", "testdata", "zip", "rsc.io", "quote", "@v", "v1.5.2.zip"))
+	assert.NoError(t, err)
+	assert.NoError(t, server.AddModule("go-staging", "rsc.io/quote", "v1.5.2", map[string][]byte{".zip": zipContent, ".mod": []byte(testModContent), ".info": []byte(testInfoContent)}))
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+	zipHash, err := utils.HashZip(filepath.Join("..", "testdata", "zip", "rsc.io", "quote", "@v", "v1.5.2.zip"))
+	assert.NoError(t, err)
+	goModHash, err := utils.HashGoMod([]byte(testModContent))
+	assert.NoError(t, err)
+
+	// A wrong checksum in go.sum fails the module before it is published.
+	goSumPath := filepath.Join(server.RepoDir("go-staging"), "go.sum")
+	assert.NoError(t, ioutil.WriteFile(goSumPath, []byte("rsc.io/quote v1.5.2 h1:wrong=\n"), 0644))
+	results, err := MirrorGoSum(goSumPath, server.ServiceDetails(), "go-staging", "go-local", servicesManager)
+	var checksumErr *utils.ChecksumMismatchError
+	if assert.True(t, errors.As(err, new(*MirrorError))) && assert.Len(t, results, 1) && assert.True(t, errors.As(results[0].Err, &checksumErr)) {
+		assert.Equal(t, "h1:wrong=", checksumErr.Expected)
+		assert.Equal(t, zipHash, checksumErr.Actual)
+	}
+	assert.Empty(t, server.Uploads())
+
+	// The files are published to the target, with the original info file.
+	// Versions which have only a go.mod hash aren't mirrored.
+	goSumContent := "example.com/modonly v1.0.0/go.mod h1:modonly=\nrsc.io/quote v1.5.2 " + zipHash + "\nrsc.io/quote v1.5.2/go.mod " + goModHash + "\n"
+	assert.NoError(t, ioutil.WriteFile(goSumPath, []byte(goSumContent), 0644))
+	results, err = MirrorGoSum(goSumPath, server.ServiceDetails(), "go-staging", "go-local", servicesManager)
+	assert.NoError(t, err)
+	assert.Equal(t, []MirrorResult{
+		{Path: "example.com/modonly", Version: "v1.0.0", Outcome: MirrorGoModOnly},
+		{Path: "rsc.io/quote", Version: "v1.5.2", Outcome: MirrorMirrored, ZipHash: zipHash, GoModHash: goModHash},
+	}, results)
+	uploads := server.Uploads()
+	if assert.Len(t, uploads, 3) {
+		for _, upload := range uploads {
+			assert.Equal(t, "go-local", upload.Repo)
+			if upload.Ext == ".info" {
+				assert.Equal(t, testInfoContent, string(upload.Content))
+			}
+		}
+	}
+
+	// Existing versions are skipped, and missing versions fail.
+	results, err = MirrorDependencies(map[string]bool{"rsc.io/quote@v1.5.2": true}, server.ServiceDetails(), "go-staging", "go-local", servicesManager)
+	assert.NoError(t, err)
+	assert.Equal(t, MirrorSkipped, results[0].Outcome)
+	results, err = MirrorDependencies(map[string]bool{"rsc.io/quote@v1.5.3": true}, server.ServiceDetails(), "go-staging", "go-local", servicesManager)
+	assert.Error(t, err)
+	var notFoundErr *utils.ModuleNotFoundError
+	assert.True(t, errors.As(results[0].Err, &notFoundErr))
+	assert.Len(t, server.Uploads(), 3)
+}
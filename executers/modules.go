@@ -0,0 +1,61 @@
+package executers
+
+import (
+	"fmt"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/params"
+)
+
+// CreateModulesBuildInfo returns a build-info module for each of the modules.
+// The modules which failed are reported in the returned cmd.ModulesErrors, while the build-info modules of the others are still returned.
+// The dependencies checks of the options, such as a NoticesCheck, are run on the dependencies of each module, and a module which fails a check is not returned.
+func CreateModulesBuildInfo(modules []cmd.LocalModule, cachePath string, options ...utils.Option) ([]buildinfo.Module, error) {
+	modulesDependencies, err := cmd.GetModulesDependenciesList(modules, options...)
+	modulesErrors, _ := err.(cmd.ModulesErrors)
+	if err != nil && modulesErrors == nil {
+		return nil, err
+	}
+	var buildInfoModules []buildinfo.Module
+	for _, moduleDependencies := range modulesDependencies {
+		delete(moduleDependencies.Dependencies, moduleDependencies.Module.Path+"@")
+		buildInfoModule, err := createBuildInfoModule(moduleDependencies.Module.Path, moduleDependencies.Module.Dir, moduleDependencies.Dependencies, cachePath, options...)
+		if err != nil {
+			modulesErrors = append(modulesErrors, &cmd.ModuleError{Module: moduleDependencies.Module, Err: err})
+			continue
+		}
+		buildInfoModules = append(buildInfoModules, *buildInfoModule)
+	}
+	return buildInfoModules, modulesErrors.ErrorOrNil()
+}
+
+// PublishModulesDependencies publishes the dependencies of each of the modules from the Go cache to all the deployers, see PublishToDeployers.
+// Dependencies shared by several modules are published once to each deployer, since the published dependencies are tracked by the target caches of dependenciesCache, which may be nil.
+// The modules which failed are reported in the returned cmd.ModulesErrors, after all the modules were processed.
+// The dependencies checks of the options, such as a NoticesCheck, are run on the dependencies of each module, and the dependencies of a module which fails a check are not published.
+func PublishModulesDependencies(modules []cmd.LocalModule, cachePath string, resolverDeployer *params.ResolverDeployer, dependenciesCache *cache.DependenciesCache, options ...utils.Option) error {
+	logger := utils.NewOptions(options...).GetLogger()
+	if dependenciesCache == nil {
+		dependenciesCache = &cache.DependenciesCache{}
+	}
+	modulesDependencies, err := cmd.GetModulesDependenciesList(modules, options...)
+	modulesErrors, _ := err.(cmd.ModulesErrors)
+	if err != nil && modulesErrors == nil {
+		return err
+	}
+	for _, moduleDependencies := range modulesDependencies {
+		logger.Info(fmt.Sprintf("Publishing the dependencies of %s", moduleDependencies.Module.Path))
+		packages, err := GetDependencies(cachePath, moduleDependencies.Dependencies, options...)
+		if err != nil {
+			modulesErrors = append(modulesErrors, &cmd.ModuleError{Module: moduleDependencies.Module, Err: err})
+			continue
+		}
+		if err = PublishToDeployers(packages, resolverDeployer, dependenciesCache, options...); err != nil {
+			modulesErrors = append(modulesErrors, &cmd.ModuleError{Module: moduleDependencies.Module, Err: err})
+		}
+	}
+	return modulesErrors.ErrorOrNil()
+}
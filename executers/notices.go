@@ -0,0 +1,154 @@
+package executers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The retraction and deprecation notices of a dependency.
+// Like the go command, the notices are taken from the go.mod of the latest version of the module.
+type ModuleNotice struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// True if the version is in a range retracted by a 'retract' directive.
+	Retracted           bool   `json:"retracted,omitempty"`
+	RetractionRationale string `json:"retractionRationale,omitempty"`
+	// The message of the '// Deprecated:' comment on the module directive.
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+func (notice *ModuleNotice) String() string {
+	var messages []string
+	if notice.Retracted {
+		messages = append(messages, "retracted: "+notice.RetractionRationale)
+	}
+	if notice.Deprecated != "" {
+		messages = append(messages, "deprecated: "+notice.Deprecated)
+	}
+	return fmt.Sprintf("%s@%s is %s", notice.Path, notice.Version, strings.Join(messages, ", "))
+}
+
+// Returned by NoticesCheck when it is set to fail on retracted dependencies, and such dependencies are found.
+type RetractedDependenciesError struct {
+	Notices []ModuleNotice
+}
+
+func (retractedError *RetractedDependenciesError) Error() string {
+	var messages []string
+	for i := range retractedError.Notices {
+		messages = append(messages, retractedError.Notices[i].String())
+	}
+	return fmt.Sprintf("%d dependencies are retracted:\n%s", len(retractedError.Notices), strings.Join(messages, "\n"))
+}
+
+// Checks the retraction and deprecation notices of dependencies, before they are published or added to the build-info.
+// The check is a utils.DependenciesCheck, so it can be passed to the APIs which list or publish dependencies using utils.WithDependenciesCheck.
+// The notices of each module version are fetched and logged once, so the same check can be used for many modules.
+// The check may be created by NewNoticesCheck, or as a literal.
+type NoticesCheck struct {
+	// The client of the repository which the go.mod files are fetched from.
+	ProxyClient *utils.ProxyClient
+	// If true, the check fails when retracted dependencies are found. Otherwise, they are only logged.
+	FailOnRetracted bool
+	// If nil, the global logger is used.
+	Logger  log.Log
+	notices map[string]*ModuleNotice
+}
+
+func NewNoticesCheck(proxyClient *utils.ProxyClient, failOnRetracted bool) *NoticesCheck {
+	return &NoticesCheck{ProxyClient: proxyClient, FailOnRetracted: failOnRetracted}
+}
+
+// Returns the notices of the dependencies, which are in the format returned by cmd.GetDependenciesList, sorted by the module path.
+// Dependencies without notices are not returned. The notices, and the failures to fetch them, are logged as warnings. The failures don't fail the check.
+// A RetractedDependenciesError is returned if FailOnRetracted is set, and retracted dependencies are found.
+func (check *NoticesCheck) Run(dependencies map[string]bool) ([]ModuleNotice, error) {
+	var notices, retracted []ModuleNotice
+	for dependency := range dependencies {
+		notice := check.getNotice(dependency)
+		if notice == nil {
+			continue
+		}
+		notices = append(notices, *notice)
+		if notice.Retracted {
+			retracted = append(retracted, *notice)
+		}
+	}
+	sortNotices(notices)
+	if check.FailOnRetracted && len(retracted) > 0 {
+		sortNotices(retracted)
+		return notices, &RetractedDependenciesError{Notices: retracted}
+	}
+	return notices, nil
+}
+
+func (check *NoticesCheck) getNotice(dependency string) *ModuleNotice {
+	if notice, exists := check.notices[dependency]; exists {
+		return notice
+	}
+	var notice *ModuleNotice
+	modulePath, version := splitDependency(dependency)
+	if version != "" {
+		var err error
+		notice, err = GetModuleNotice(modulePath, version, check.ProxyClient)
+		if err != nil {
+			check.getLogger().Warn(fmt.Sprintf("Could not check the retractions and deprecation of %s: %s", dependency, err.Error()))
+		} else if notice != nil {
+			check.getLogger().Warn(notice.String())
+		}
+	}
+	if check.notices == nil {
+		check.notices = map[string]*ModuleNotice{}
+	}
+	check.notices[dependency] = notice
+	return notice
+}
+
+// Runs the check. Implements utils.DependenciesCheck.
+func (check *NoticesCheck) CheckDependencies(dependencies map[string]bool) error {
+	_, err := check.Run(dependencies)
+	return err
+}
+
+func (check *NoticesCheck) getLogger() log.Log {
+	if check.Logger != nil {
+		return check.Logger
+	}
+	return utils.GetGlobalLogger()
+}
+
+// Returns the notices of the module version, or nil if the version is not retracted and the module is not deprecated.
+func GetModuleNotice(modulePath, version string, proxyClient *utils.ProxyClient) (*ModuleNotice, error) {
+	latest, err := getLatestVersion(modulePath, proxyClient)
+	if err != nil || latest == "" {
+		return nil, err
+	}
+	return getModuleNotice(modulePath, version, latest, proxyClient)
+}
+
+// Returns the notices of the module version from the go.mod of the latest version, or nil if there are none.
+func getModuleNotice(modulePath, version, latest string, proxyClient *utils.ProxyClient) (*ModuleNotice, error) {
+	goMod, err := getGoMod(modulePath, latest, proxyClient)
+	if err != nil || goMod == nil {
+		return nil, err
+	}
+	notice := &ModuleNotice{Path: modulePath, Version: version, Deprecated: goMod.Deprecated}
+	if retract := goMod.GetRetraction(version); retract != nil {
+		notice.Retracted = true
+		notice.RetractionRationale = retract.Rationale
+	}
+	if !notice.Retracted && notice.Deprecated == "" {
+		return nil, nil
+	}
+	return notice, nil
+}
+
+func sortNotices(notices []ModuleNotice) {
+	sort.Slice(notices, func(i, j int) bool {
+		return notices[i].Path < notices[j].Path
+	})
+}
@@ -0,0 +1,97 @@
+package executers
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoticesCheck(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	mods := map[string]map[string]string{
+		"github.com/jfrog/lib": {
+			"v1.0.0": "module github.com/jfrog/lib\n",
+			"v1.1.0": "module github.com/jfrog/lib\n\nretract [v1.0.0, v1.0.5] // Data race\n",
+		},
+		"github.com/jfrog/old": {
+			"v0.1.0": "// Deprecated: use github.com/jfrog/new instead.\nmodule github.com/jfrog/old\n",
+		},
+		"github.com/jfrog/fine": {
+			"v1.0.0": "module github.com/jfrog/fine\n",
+		},
+	}
+	for modulePath, versions := range mods {
+		for version, mod := range versions {
+			assert.NoError(t, server.AddModule("go-local", modulePath, version, map[string][]byte{".mod": []byte(mod)}))
+		}
+	}
+	proxyClient, err := utils.NewProxyClient(server.ServiceDetails(), "go-local")
+	assert.NoError(t, err)
+	dependencies := map[string]bool{
+		"github.com/jfrog/main@":          true,
+		"github.com/jfrog/lib@v1.0.0":     true,
+		"github.com/jfrog/old@v0.1.0":     true,
+		"github.com/jfrog/fine@v1.0.0":    true,
+		"github.com/jfrog/missing@v1.0.0": true,
+	}
+	expected := []ModuleNotice{
+		{Path: "github.com/jfrog/lib", Version: "v1.0.0", Retracted: true, RetractionRationale: "Data race"},
+		{Path: "github.com/jfrog/old", Version: "v0.1.0", Deprecated: "use github.com/jfrog/new instead."},
+	}
+
+	notices, err := NewNoticesCheck(proxyClient, false).Run(dependencies)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, notices)
+
+	check := NewNoticesCheck(proxyClient, true)
+	notices, err = check.Run(dependencies)
+	assert.Equal(t, expected, notices)
+	var retractedError *RetractedDependenciesError
+	if assert.True(t, errors.As(err, &retractedError)) {
+		assert.Equal(t, expected[:1], retractedError.Notices)
+	}
+	// The notices are fetched once.
+	requestsCount := len(server.Requests())
+	_, err = check.Run(dependencies)
+	assert.Error(t, err)
+	assert.Len(t, server.Requests(), requestsCount)
+
+	// A check created as a literal works as well.
+	literalCheck := &NoticesCheck{ProxyClient: proxyClient, FailOnRetracted: true}
+	assert.True(t, errors.As(literalCheck.CheckDependencies(dependencies), &retractedError))
+
+	// The latest version is not retracted.
+	notice, err := GetModuleNotice("github.com/jfrog/lib", "v1.1.0", proxyClient)
+	assert.NoError(t, err)
+	assert.Nil(t, notice)
+}
+
+func TestNoticesCheckOption(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	assert.NoError(t, server.AddRepo("go-remote"))
+	assert.NoError(t, server.AddModule("go-remote", "github.com/jfrog/lib", "v1.1.0", map[string][]byte{".mod": []byte("module github.com/jfrog/lib\n\nretract v1.0.0 // Data race\n")}))
+	proxyClient, err := utils.NewProxyClient(server.ServiceDetails(), "go-remote")
+	assert.NoError(t, err)
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	createTestModuleZip(t, cachePath, "github.com/jfrog/lib", "v1.0.0", map[string]string{"go.mod": "module github.com/jfrog/lib\n"})
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cachePath, "github.com", "jfrog", "lib", "@v", "v1.0.0.mod"), []byte("module github.com/jfrog/lib\n"), 0644))
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+
+	// The check runs on the packages before they are published.
+	packages, err := GetDependencies(cachePath, map[string]bool{"github.com/jfrog/lib@v1.0.0": true}, utils.WithDependenciesCheck(NewNoticesCheck(proxyClient, true)))
+	assert.NoError(t, err)
+	if assert.Len(t, packages, 1) {
+		var retractedError *RetractedDependenciesError
+		assert.True(t, errors.As(packages[0].Publish("", "go-local", servicesManager), &retractedError))
+	}
+	assert.Empty(t, server.Uploads())
+}
@@ -0,0 +1,190 @@
+package executers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// The available upgrades of a module, as reported by GetOutdatedDependencies.
+// The versions are empty if there is no newer version.
+type OutdatedModule struct {
+	Path    string `json:"path"`
+	Current string `json:"current"`
+	// The latest release with the same major and minor versions as the current version.
+	LatestPatch string `json:"latestPatch,omitempty"`
+	// The latest release with the same major version as the current version, and a newer minor version.
+	LatestMinor string `json:"latestMinor,omitempty"`
+	// The latest newer major version of the module, which has a different module path (for example, example.com/mod/v2).
+	LatestMajorPath string `json:"latestMajorPath,omitempty"`
+	LatestMajor     string `json:"latestMajor,omitempty"`
+	// True if the current version is retracted by the latest version of the module.
+	Retracted           bool   `json:"retracted,omitempty"`
+	RetractionRationale string `json:"retractionRationale,omitempty"`
+	// The reason the module could not be checked.
+	Error string `json:"error,omitempty"`
+}
+
+// Returns true if a newer version of the module is available, or if the current version is retracted.
+func (outdated *OutdatedModule) IsOutdated() bool {
+	return outdated.LatestPatch != "" || outdated.LatestMinor != "" || outdated.LatestMajor != "" || outdated.Retracted
+}
+
+// Checks the available upgrades of the dependencies, using the versions in the Go repository of the proxy client.
+// The dependencies are in the format returned by cmd.GetDependenciesList. Modules without a version, such as the main module, are skipped.
+// A module which could not be checked is returned with its Error field set. The results are sorted by the module path.
+func GetOutdatedDependencies(dependencies map[string]bool, proxyClient *utils.ProxyClient, options ...utils.Option) []OutdatedModule {
+	logger := utils.NewOptions(options...).GetLogger()
+	var results []OutdatedModule
+	for dependency := range dependencies {
+		modulePath, version := splitDependency(dependency)
+		if version == "" {
+			continue
+		}
+		outdated, err := getOutdatedModule(modulePath, version, proxyClient)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Could not check the available versions of %s: %s", dependency, err.Error()))
+			outdated.Error = err.Error()
+		}
+		results = append(results, *outdated)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+	return results
+}
+
+// Splits a dependency in the format 'path@version'.
+func splitDependency(dependency string) (modulePath, version string) {
+	index := strings.LastIndex(dependency, "@")
+	if index < 0 {
+		return dependency, ""
+	}
+	return dependency[:index], dependency[index+1:]
+}
+
+func getOutdatedModule(modulePath, version string, proxyClient *utils.ProxyClient) (*OutdatedModule, error) {
+	outdated := &OutdatedModule{Path: modulePath, Current: version}
+	versions, err := proxyClient.List(modulePath)
+	if err != nil && !isModuleMissing(err) {
+		return outdated, err
+	}
+	latest, err := getLatestVersion(modulePath, proxyClient)
+	if err != nil {
+		return outdated, err
+	}
+	if latest != "" {
+		// The latest version may be a pseudo-version, which is not included in the list.
+		versions = append(versions, latest)
+	}
+	outdated.LatestPatch = getLatestUpgrade(version, versions, isPatchUpgrade)
+	outdated.LatestMinor = getLatestUpgrade(version, versions, isMinorUpgrade)
+	if latest != "" {
+		notice, err := getModuleNotice(modulePath, version, latest, proxyClient)
+		if err != nil {
+			return outdated, err
+		}
+		if notice != nil {
+			outdated.Retracted, outdated.RetractionRationale = notice.Retracted, notice.RetractionRationale
+		}
+	}
+	outdated.LatestMajorPath, outdated.LatestMajor, err = getLatestMajor(modulePath, proxyClient)
+	return outdated, err
+}
+
+// Returns the version returned by the @latest endpoint, or an empty string if the module has no versions.
+func getLatestVersion(modulePath string, proxyClient *utils.ProxyClient) (string, error) {
+	info, err := proxyClient.Latest(modulePath)
+	if err != nil {
+		if isModuleMissing(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return info.Version, nil
+}
+
+// Returns the latest release which is newer than the current version, and is accepted as an upgrade of the current version by isUpgrade.
+// Pre-releases are considered only if the current version is a pre-release.
+func getLatestUpgrade(current string, versions []string, isUpgrade func(current, version string) bool) string {
+	latest := ""
+	for _, version := range versions {
+		if !semver.IsValid(version) || !isUpgrade(current, version) || semver.Compare(version, current) <= 0 {
+			continue
+		}
+		if semver.Prerelease(version) != "" && semver.Prerelease(current) == "" {
+			continue
+		}
+		if latest == "" || semver.Compare(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// Returns true if the version has the same major and minor versions as the current version.
+func isPatchUpgrade(current, version string) bool {
+	return semver.MajorMinor(version) == semver.MajorMinor(current)
+}
+
+// Returns true if the version has the same major version as the current version, and a different minor version.
+// Together with the check that the version is newer, this means that its minor version is greater.
+func isMinorUpgrade(current, version string) bool {
+	return semver.Major(version) == semver.Major(current) && semver.MajorMinor(version) != semver.MajorMinor(current)
+}
+
+// Downloads and parses the go.mod of the module version. Returns nil if it doesn't exist in the repository.
+func getGoMod(modulePath, version string, proxyClient *utils.ProxyClient) (*utils.GoMod, error) {
+	content, err := proxyClient.Mod(modulePath, version)
+	if err != nil {
+		if isModuleMissing(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return utils.ParseGoMod(modulePath+"@"+version+"/go.mod", content)
+}
+
+// Returns the latest version of the highest major version of the module, which is newer than the major version of modulePath.
+// The major versions are checked one after the other, starting from the next one, until a major version is not found.
+func getLatestMajor(modulePath string, proxyClient *utils.ProxyClient) (latestPath, latestVersion string, err error) {
+	prefix, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return "", "", nil
+	}
+	major := 1
+	if pathMajor != "" {
+		major, err = strconv.Atoi(strings.TrimLeft(pathMajor, "/.v"))
+		if err != nil {
+			return "", "", nil
+		}
+	}
+	separator := "/v"
+	if strings.HasPrefix(modulePath, "gopkg.in/") {
+		separator = ".v"
+	}
+	for major++; ; major++ {
+		candidatePath := prefix + separator + strconv.Itoa(major)
+		version, err := getLatestVersion(candidatePath, proxyClient)
+		if err != nil {
+			return latestPath, latestVersion, err
+		}
+		if version == "" {
+			return latestPath, latestVersion, nil
+		}
+		latestPath, latestVersion = candidatePath, version
+	}
+}
+
+// Returns true if the error indicates the module or version doesn't exist in the repository.
+func isModuleMissing(err error) bool {
+	var notFound *utils.ModuleNotFoundError
+	var gone *utils.ModuleGoneError
+	return errors.As(err, &notFound) || errors.As(err, &gone)
+}
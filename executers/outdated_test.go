@@ -0,0 +1,73 @@
+package executers
+
+import (
+	"testing"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOutdatedDependencies(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	modules := map[string][]string{
+		"github.com/jfrog/lib":    {"v1.0.0", "v1.0.1", "v1.0.2", "v1.1.0", "v1.2.0-rc1"},
+		"github.com/jfrog/lib/v2": {"v2.0.0", "v2.1.0"},
+		"github.com/jfrog/lib/v3": {"v3.0.0"},
+		"github.com/jfrog/other":  {"v0.1.0"},
+		"gopkg.in/yaml.v2":        {"v2.4.0"},
+		"gopkg.in/yaml.v3":        {"v3.0.1"},
+	}
+	for modulePath, versions := range modules {
+		for _, version := range versions {
+			mod := "module " + modulePath + "\n"
+			if modulePath == "github.com/jfrog/lib" && version == "v1.1.0" {
+				mod += "retract v1.0.1 // Broken build\n"
+			}
+			assert.NoError(t, server.AddModule("go-local", modulePath, version, map[string][]byte{".mod": []byte(mod)}))
+		}
+	}
+	proxyClient, err := utils.NewProxyClient(server.ServiceDetails(), "go-local")
+	assert.NoError(t, err)
+
+	dependencies := map[string]bool{
+		"github.com/jfrog/main@":          true,
+		"github.com/jfrog/lib@v1.0.1":     true,
+		"github.com/jfrog/other@v0.1.0":   true,
+		"gopkg.in/yaml.v2@v2.4.0":         true,
+		"github.com/jfrog/missing@v1.0.0": true,
+	}
+	results := GetOutdatedDependencies(dependencies, proxyClient)
+	if !assert.Len(t, results, 4) {
+		return
+	}
+	assert.Equal(t, OutdatedModule{
+		Path:                "github.com/jfrog/lib",
+		Current:             "v1.0.1",
+		LatestPatch:         "v1.0.2",
+		LatestMinor:         "v1.1.0",
+		LatestMajorPath:     "github.com/jfrog/lib/v3",
+		LatestMajor:         "v3.0.0",
+		Retracted:           true,
+		RetractionRationale: "Broken build",
+	}, results[0])
+	assert.True(t, results[0].IsOutdated())
+
+	// A module which doesn't exist in the repository has no upgrades.
+	assert.Equal(t, OutdatedModule{Path: "github.com/jfrog/missing", Current: "v1.0.0"}, results[1])
+	assert.False(t, results[1].IsOutdated())
+
+	assert.Equal(t, OutdatedModule{Path: "github.com/jfrog/other", Current: "v0.1.0"}, results[2])
+	assert.Equal(t, OutdatedModule{Path: "gopkg.in/yaml.v2", Current: "v2.4.0", LatestMajorPath: "gopkg.in/yaml.v3", LatestMajor: "v3.0.1"}, results[3])
+}
+
+func TestGetLatestUpgrade(t *testing.T) {
+	versions := []string{"v1.2.3", "v1.2.4", "v1.3.0", "v1.4.0-beta", "v2.0.0+incompatible", "invalid"}
+	assert.Equal(t, "v1.2.4", getLatestUpgrade("v1.2.3", versions, isPatchUpgrade))
+	assert.Equal(t, "v1.3.0", getLatestUpgrade("v1.2.3", versions, isMinorUpgrade))
+	assert.Equal(t, "v1.4.0-beta", getLatestUpgrade("v1.4.0-alpha", versions, isPatchUpgrade))
+	assert.Empty(t, getLatestUpgrade("v1.4.0-alpha", versions, isMinorUpgrade))
+	assert.Empty(t, getLatestUpgrade("v1.3.0", versions, isPatchUpgrade))
+	// The latest minor upgrade is not the latest patch of the current minor version.
+	assert.Empty(t, getLatestUpgrade("v1.3.0", []string{"v1.3.0", "v1.3.1"}, isMinorUpgrade))
+}
@@ -0,0 +1,380 @@
+package executers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+type PolicyAction string
+
+const (
+	// Violations are logged as warnings.
+	PolicyActionWarn PolicyAction = "warn"
+	// Violations are logged, and a PolicyViolationsError is returned. This is the default action.
+	PolicyActionFail PolicyAction = "fail"
+)
+
+// The rules which were violated, as reported in PolicyViolation.
+const (
+	PolicyRuleDeny    = "deny"
+	PolicyRuleAllow   = "allow"
+	PolicyRuleLicense = "license"
+	PolicyRuleMaxAge  = "maxAge"
+)
+
+// A policy for the dependencies which may be resolved or published, loaded from a JSON file:
+//
+//	{
+//	  "action": "fail",
+//	  "allow": [{"path": "github.com/jfrog/..."}, {"path": "golang.org/x/*"}],
+//	  "deny": [{"path": "github.com/forked-org/...", "reason": "Forks are not allowed"}, {"path": "github.com/jfrog/lib", "versions": ">=v1.0.0 <v1.2.3"}],
+//	  "denyLicenses": ["GPL-3.0-only", "AGPL-3.0-only"],
+//	  "maxAgeDays": 730
+//	}
+type Policy struct {
+	Action PolicyAction `json:"action,omitempty"`
+	// If not empty, every dependency must match at least one of the constraints.
+	Allow []ModuleConstraint `json:"allow,omitempty"`
+	// Dependencies which match one of the constraints are denied.
+	Deny []ModuleConstraint `json:"deny,omitempty"`
+	// SPDX identifiers of denied licenses. The licenses are detected in the zips of the dependencies in the Go cache.
+	DenyLicenses []string `json:"denyLicenses,omitempty"`
+	// If positive, dependencies which were published more than the number of days ago are denied.
+	// The time of a version is taken from its .info file in the Go cache, or from the pseudo-version.
+	MaxAgeDays int `json:"maxAgeDays,omitempty"`
+	// Returns the current time. Used for testing.
+	now    func() time.Time
+	logger log.Log
+}
+
+// Matches module versions by a module path pattern and an optional versions range.
+type ModuleConstraint struct {
+	// The module path pattern. '*' matches any string without a slash, and '...' matches any string, as in go command patterns.
+	Path string `json:"path"`
+	// Space-separated version comparisons, which all must match, such as ">=v1.0.0 <v1.2.3". Empty matches all the versions.
+	// The supported operators are =, !=, <, <=, > and >=.
+	Versions    string `json:"versions,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	pathRegExp  *regexp.Regexp
+	comparisons []versionComparison
+}
+
+type versionComparison struct {
+	operator string
+	version  string
+}
+
+// A dependency which violates the policy.
+type PolicyViolation struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// One of the PolicyRule constants.
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (violation *PolicyViolation) String() string {
+	return fmt.Sprintf("%s@%s violates the %s rule: %s", violation.Path, violation.Version, violation.Rule, violation.Message)
+}
+
+// Returned when the policy action is fail, and violations are found.
+type PolicyViolationsError struct {
+	Violations []PolicyViolation
+}
+
+func (violationsError *PolicyViolationsError) Error() string {
+	var messages []string
+	for i := range violationsError.Violations {
+		messages = append(messages, violationsError.Violations[i].String())
+	}
+	return fmt.Sprintf("%d dependencies policy violations were found:\n%s", len(violationsError.Violations), strings.Join(messages, "\n"))
+}
+
+// Reads and validates a policy file.
+func LoadPolicy(path string) (*Policy, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	policy := &Policy{}
+	if err = json.Unmarshal(content, policy); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Failed parsing the policy file %s: %s", path, err.Error()))
+	}
+	if err = policy.Init(); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid policy file %s: %s", path, err.Error()))
+	}
+	return policy, nil
+}
+
+// Validates the policy and prepares its constraints. A policy which was not loaded by LoadPolicy, such as a literal, is initialized when it is first evaluated.
+func (policy *Policy) Init() error {
+	switch policy.Action {
+	case "":
+		policy.Action = PolicyActionFail
+	case PolicyActionWarn, PolicyActionFail:
+	default:
+		return errors.New("unknown action: " + string(policy.Action))
+	}
+	for _, constraints := range [][]ModuleConstraint{policy.Allow, policy.Deny} {
+		for i := range constraints {
+			if err := constraints[i].init(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Runs Init if the policy or any of its constraints weren't initialized.
+func (policy *Policy) initIfNeeded() error {
+	if policy.Action == "" {
+		return policy.Init()
+	}
+	for _, constraints := range [][]ModuleConstraint{policy.Allow, policy.Deny} {
+		for i := range constraints {
+			if constraints[i].pathRegExp == nil {
+				return policy.Init()
+			}
+		}
+	}
+	return nil
+}
+
+func (constraint *ModuleConstraint) init() error {
+	if constraint.Path == "" {
+		return errors.New("a module constraint must include a path")
+	}
+	pattern := regexp.QuoteMeta(constraint.Path)
+	// Like the patterns of the go command, x/... matches x as well as its subdirectories.
+	pattern = strings.Replace(pattern, `/\.\.\.`, `(/.*)?`, -1)
+	pattern = strings.Replace(pattern, `\.\.\.`, `.*`, -1)
+	pattern = strings.Replace(pattern, `\*`, `[^/]*`, -1)
+	constraint.pathRegExp = regexp.MustCompile("^" + pattern + "$")
+	constraint.comparisons = nil
+	for _, field := range strings.Fields(constraint.Versions) {
+		comparison := versionComparison{operator: "=", version: field}
+		for _, operator := range []string{">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(field, operator) {
+				comparison = versionComparison{operator: operator, version: strings.TrimPrefix(field, operator)}
+				break
+			}
+		}
+		if !semver.IsValid(comparison.version) {
+			return fmt.Errorf("invalid version '%s' in the constraint of %s", comparison.version, constraint.Path)
+		}
+		constraint.comparisons = append(constraint.comparisons, comparison)
+	}
+	return nil
+}
+
+// Returns true if the module version matches the path pattern and the versions range.
+// A constraint which wasn't initialized is initialized first, and an invalid constraint matches nothing.
+func (constraint *ModuleConstraint) Matches(modulePath, version string) bool {
+	if constraint.pathRegExp == nil && constraint.init() != nil {
+		return false
+	}
+	if !constraint.pathRegExp.MatchString(modulePath) {
+		return false
+	}
+	for _, comparison := range constraint.comparisons {
+		result := semver.Compare(version, comparison.version)
+		var matches bool
+		switch comparison.operator {
+		case "=":
+			matches = result == 0
+		case "!=":
+			matches = result != 0
+		case "<":
+			matches = result < 0
+		case "<=":
+			matches = result <= 0
+		case ">":
+			matches = result > 0
+		case ">=":
+			matches = result >= 0
+		}
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluates the policy against the dependencies, which are in the format returned by cmd.GetDependenciesList.
+// The Go cache is used for detecting the licenses and the times of the dependencies.
+// The violations are returned sorted by the module path. If the policy action is fail and violations are found, a PolicyViolationsError is returned too.
+func (policy *Policy) Evaluate(cachePath string, dependencies map[string]bool) ([]PolicyViolation, error) {
+	if err := policy.initIfNeeded(); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid policy: %s", err.Error()))
+	}
+	var violations []PolicyViolation
+	for dependency := range dependencies {
+		modulePath, version := splitDependency(dependency)
+		if version == "" {
+			continue
+		}
+		encodedPath, encodedVersion := goModEncode(modulePath), goModEncode(version)
+		zipPath, err := getPackageZipLocation(cachePath, encodedPath, encodedVersion, policy.getLogger())
+		if err != nil {
+			return nil, err
+		}
+		infoPath := ""
+		if zipPath != "" {
+			infoPath = filepath.Join(filepath.Dir(zipPath), encodedVersion+".info")
+		}
+		moduleViolations, err := policy.evaluateModule(modulePath, version, zipPath, infoPath)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, moduleViolations...)
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	return violations, policy.result(violations, policy.getLogger())
+}
+
+// Evaluates the policy against the dependencies, using the Go cache of the current environment. Implements utils.DependenciesCheck,
+// so the policy can be passed to cmd.RunGo or to the publishing APIs using utils.WithDependenciesCheck.
+func (policy *Policy) CheckDependencies(dependencies map[string]bool) error {
+	cachePath, err := cmd.GetCachePath()
+	if err != nil {
+		return err
+	}
+	_, err = policy.Evaluate(cachePath, dependencies)
+	return err
+}
+
+// Sets the logger used for reporting the violations, instead of the global logger.
+func (policy *Policy) SetLogger(logger log.Log) {
+	policy.logger = logger
+}
+
+func (policy *Policy) getLogger() log.Log {
+	if policy.logger != nil {
+		return policy.logger
+	}
+	return utils.GetGlobalLogger()
+}
+
+// Logs the violations, and returns a PolicyViolationsError if the policy action is fail.
+func (policy *Policy) result(violations []PolicyViolation, logger log.Log) error {
+	for i := range violations {
+		logger.Warn(violations[i].String())
+	}
+	if len(violations) > 0 && policy.Action != PolicyActionWarn {
+		return &PolicyViolationsError{Violations: violations}
+	}
+	return nil
+}
+
+// Evaluates the policy against a module version. The zip and info files are optional.
+func (policy *Policy) evaluateModule(modulePath, version, zipPath, infoPath string) ([]PolicyViolation, error) {
+	var violations []PolicyViolation
+	addViolation := func(rule, message string) {
+		violations = append(violations, PolicyViolation{Path: modulePath, Version: version, Rule: rule, Message: message})
+	}
+	for i := range policy.Deny {
+		if policy.Deny[i].Matches(modulePath, version) {
+			addViolation(PolicyRuleDeny, constraintMessage(&policy.Deny[i], "denied by "))
+			break
+		}
+	}
+	if len(policy.Allow) > 0 && !policy.isAllowed(modulePath, version) {
+		addViolation(PolicyRuleAllow, "not in the allow list")
+	}
+	if len(policy.DenyLicenses) > 0 && zipPath != "" {
+		licenses, err := detectZipLicenses(zipPath, modulePath, version, policy.getLogger())
+		if err != nil {
+			return nil, err
+		}
+		for _, license := range licenses {
+			if containsString(policy.DenyLicenses, license.SpdxId) {
+				addViolation(PolicyRuleLicense, fmt.Sprintf("the license %s found in %s is denied", license.SpdxId, license.File))
+			}
+		}
+	}
+	if policy.MaxAgeDays > 0 {
+		versionTime, err := getVersionTime(version, infoPath)
+		if err != nil {
+			return nil, err
+		}
+		maxAge := time.Duration(policy.MaxAgeDays) * 24 * time.Hour
+		if !versionTime.IsZero() && policy.currentTime().Sub(versionTime) > maxAge {
+			addViolation(PolicyRuleMaxAge, fmt.Sprintf("published on %s, more than %d days ago", versionTime.Format("2006-01-02"), policy.MaxAgeDays))
+		}
+	}
+	return violations, nil
+}
+
+func (policy *Policy) currentTime() time.Time {
+	if policy.now != nil {
+		return policy.now()
+	}
+	return time.Now()
+}
+
+func (policy *Policy) isAllowed(modulePath, version string) bool {
+	for i := range policy.Allow {
+		if policy.Allow[i].Matches(modulePath, version) {
+			return true
+		}
+	}
+	return false
+}
+
+func constraintMessage(constraint *ModuleConstraint, prefix string) string {
+	message := prefix + constraint.Path
+	if constraint.Versions != "" {
+		message += " " + constraint.Versions
+	}
+	if constraint.Reason != "" {
+		message += ": " + constraint.Reason
+	}
+	return message
+}
+
+// Returns the time of the version from its .info file, or from the pseudo-version.
+// Returns a zero time if the time is unknown.
+func getVersionTime(version, infoPath string) (time.Time, error) {
+	if infoPath != "" && fileutils.IsPathExists(infoPath, false) {
+		content, err := ioutil.ReadFile(infoPath)
+		if err != nil {
+			return time.Time{}, errorutils.CheckError(err)
+		}
+		info := &utils.ModuleInfo{}
+		if err = json.Unmarshal(content, info); err != nil {
+			return time.Time{}, errorutils.CheckError(err)
+		}
+		return info.Time, nil
+	}
+	if module.IsPseudoVersion(version) {
+		return module.PseudoVersionTime(version)
+	}
+	log.Debug("The time of the version is unknown:", version)
+	return time.Time{}, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
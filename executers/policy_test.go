@@ -0,0 +1,149 @@
+package executers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicy = `{
+  "allow": [{"path": "github.com/jfrog/..."}, {"path": "golang.org/x/*"}],
+  "deny": [
+    {"path": "github.com/jfrog/forked-*", "reason": "Forks are not allowed"},
+    {"path": "github.com/jfrog/lib", "versions": ">=v1.0.0 <v1.2.3"}
+  ],
+  "denyLicenses": ["GPL-3.0-only", "MIT"],
+  "maxAgeDays": 365
+}`
+
+func createTestPolicy(t *testing.T, content string) (*Policy, error) {
+	policyFile, err := ioutil.TempFile("", "policy*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(policyFile.Name())
+	_, err = policyFile.WriteString(content)
+	assert.NoError(t, err)
+	assert.NoError(t, policyFile.Close())
+	return LoadPolicy(policyFile.Name())
+}
+
+func TestLoadPolicy(t *testing.T) {
+	policy, err := createTestPolicy(t, testPolicy)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, PolicyActionFail, policy.Action)
+	lib := policy.Deny[1]
+	assert.True(t, lib.Matches("github.com/jfrog/lib", "v1.0.0"))
+	assert.True(t, lib.Matches("github.com/jfrog/lib", "v1.2.2"))
+	assert.False(t, lib.Matches("github.com/jfrog/lib", "v1.2.3"))
+	assert.False(t, lib.Matches("github.com/jfrog/lib/v2", "v2.0.0"))
+	assert.True(t, policy.Allow[0].Matches("github.com/jfrog/lib/v2", "v2.0.0"))
+	assert.True(t, policy.Allow[1].Matches("golang.org/x/mod", "v0.12.0"))
+	assert.False(t, policy.Allow[1].Matches("golang.org/x/mod/sub", "v0.12.0"))
+
+	// Like the patterns of the go command, x/... matches x itself.
+	forks := ModuleConstraint{Path: "github.com/forked-org/..."}
+	assert.NoError(t, forks.init())
+	assert.True(t, forks.Matches("github.com/forked-org", "v1.0.0"))
+	assert.True(t, forks.Matches("github.com/forked-org/lib/v2", "v2.0.0"))
+	assert.False(t, forks.Matches("github.com/forked-organization", "v1.0.0"))
+
+	for _, invalid := range []string{
+		`{"action": "ignore"}`,
+		`{"deny": [{"versions": "v1.0.0"}]}`,
+		`{"deny": [{"path": "github.com/jfrog/lib", "versions": "<1.0.0"}]}`,
+		`{"deny": [{"path": "github.com/jfrog/lib", "versions": "~v1.0.0"}]}`,
+		`not json`,
+	} {
+		_, err = createTestPolicy(t, invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	cachePath, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer fileutils.RemoveTempDir(cachePath)
+	createTestModuleZip(t, cachePath, "github.com/jfrog/lib", "v1.0.0", map[string]string{"LICENSE": mitLicense})
+	createTestModuleZip(t, cachePath, "github.com/jfrog/new", "v1.0.0", map[string]string{"LICENSE": bsd3License})
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cachePath, "github.com", "jfrog", "lib", "@v", "v1.0.0.info"), []byte(`{"Version":"v1.0.0","Time":"2019-01-01T00:00:00Z"}`), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(cachePath, "github.com", "jfrog", "new", "@v", "v1.0.0.info"), []byte(`{"Version":"v1.0.0","Time":"2021-12-01T00:00:00Z"}`), 0644))
+
+	policy, err := createTestPolicy(t, testPolicy)
+	if !assert.NoError(t, err) {
+		return
+	}
+	policy.now = func() time.Time {
+		return time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	dependencies := map[string]bool{
+		"github.com/jfrog/main@":                                         true,
+		"github.com/jfrog/lib@v1.0.0":                                    true,
+		"github.com/jfrog/new@v1.0.0":                                    true,
+		"github.com/jfrog/forked-lib@v0.0.0-20180101000000-abcdefabcdef": true,
+		"example.com/other@v1.0.0":                                       true,
+	}
+	violations, err := policy.Evaluate(cachePath, dependencies)
+	var violationsError *PolicyViolationsError
+	if assert.ErrorAs(t, err, &violationsError) {
+		assert.Equal(t, violations, violationsError.Violations)
+	}
+	assert.Equal(t, []PolicyViolation{
+		{Path: "example.com/other", Version: "v1.0.0", Rule: PolicyRuleAllow, Message: "not in the allow list"},
+		{Path: "github.com/jfrog/forked-lib", Version: "v0.0.0-20180101000000-abcdefabcdef", Rule: PolicyRuleDeny, Message: "denied by github.com/jfrog/forked-*: Forks are not allowed"},
+		{Path: "github.com/jfrog/forked-lib", Version: "v0.0.0-20180101000000-abcdefabcdef", Rule: PolicyRuleMaxAge, Message: "published on 2018-01-01, more than 365 days ago"},
+		{Path: "github.com/jfrog/lib", Version: "v1.0.0", Rule: PolicyRuleDeny, Message: "denied by github.com/jfrog/lib >=v1.0.0 <v1.2.3"},
+		{Path: "github.com/jfrog/lib", Version: "v1.0.0", Rule: PolicyRuleLicense, Message: "the license MIT found in LICENSE is denied"},
+		{Path: "github.com/jfrog/lib", Version: "v1.0.0", Rule: PolicyRuleMaxAge, Message: "published on 2019-01-01, more than 365 days ago"},
+	}, violations)
+
+	// In warn mode, the violations are returned without an error.
+	policy.Action = PolicyActionWarn
+	warnings, err := policy.Evaluate(cachePath, dependencies)
+	assert.NoError(t, err)
+	assert.Equal(t, violations, warnings)
+
+	// The policy is a dependencies check.
+	policy.Action = PolicyActionFail
+	var check utils.DependenciesCheck = policy
+	assert.ErrorAs(t, check.CheckDependencies(map[string]bool{"example.com/other@v1.0.0": true}), &violationsError)
+	assert.NoError(t, check.CheckDependencies(map[string]bool{"github.com/jfrog/new@v1.0.0": true}))
+
+	// A literal policy is initialized when it is evaluated.
+	literal := &Policy{Deny: []ModuleConstraint{{Path: "github.com/jfrog/..."}}}
+	_, err = literal.Evaluate(cachePath, map[string]bool{"github.com/jfrog/lib@v1.0.0": true})
+	assert.ErrorAs(t, err, &violationsError)
+	assert.Equal(t, PolicyActionFail, literal.Action)
+	assert.True(t, (&ModuleConstraint{Path: "github.com/jfrog/*"}).Matches("github.com/jfrog/lib", "v1.0.0"))
+	_, err = (&Policy{Deny: []ModuleConstraint{{}}}).Evaluate(cachePath, map[string]bool{"github.com/jfrog/lib@v1.0.0": true})
+	assert.EqualError(t, err, "Invalid policy: a module constraint must include a path")
+}
+
+func TestPublishWithPolicy(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+	dep, err := createDependency(cachePath, "rsc.io/quote", "v1.5.2", utils.NewOptions())
+	assert.NoError(t, err)
+
+	policy, err := createTestPolicy(t, `{"deny": [{"path": "rsc.io/..."}]}`)
+	assert.NoError(t, err)
+	dep.AddDependenciesCheck(policy)
+	assert.ErrorAs(t, dep.Publish("", "go-local", servicesManager), new(*PolicyViolationsError))
+	assert.Empty(t, server.Uploads())
+
+	policy.Action = PolicyActionWarn
+	assert.NoError(t, dep.Publish("", "go-local", servicesManager))
+	assert.Len(t, server.Uploads(), 3)
+}
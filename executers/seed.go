@@ -0,0 +1,164 @@
+package executers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/params"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// Seeds the deployer Go repositories with the module versions listed in a go.sum file, for example to prepare them for an air-gapped site.
+// The versions are published to all the deployers, such as a primary Artifactory and a disaster recovery instance.
+// The versions are downloaded into the cache from the resolver repository, and directly from VCS if they are missing from it, unless noFallback is set.
+// If the resolver is not set, the versions are downloaded directly from VCS.
+// The h1 hashes of the downloaded .zip and .mod files are verified against the go.sum before they are published.
+// Versions which have only a go.mod hash in the go.sum are skipped, since their zips can't be verified.
+// Seeding can be resumed: versions which exist in a deployer repository are skipped for it, and versions which exist in the cache aren't downloaded.
+// The outcome of each version is recorded in the target cache of the deployer in dependenciesCache, which may be nil, and the report of the cache is returned.
+// If some of the versions failed, an error is returned along with the report.
+func SeedFromGoSum(goSumPath, cachePath string, resolverDeployer *params.ResolverDeployer, noFallback bool, dependenciesCache *cache.DependenciesCache, options ...utils.Option) (*cache.PublishReport, error) {
+	opts := utils.NewOptions(options...)
+	logger := opts.GetLogger()
+	var deployers []*params.Params
+	for _, deployer := range resolverDeployer.Deployers() {
+		if deployer != nil && !deployer.IsEmpty() {
+			deployers = append(deployers, deployer)
+		}
+	}
+	if len(deployers) == 0 {
+		return nil, errorutils.CheckError(errors.New("a deployer is required for seeding a Go repository"))
+	}
+	if dependenciesCache == nil {
+		dependenciesCache = &cache.DependenciesCache{}
+	}
+	goSumEntries, err := utils.ReadGoSum(goSumPath)
+	if err != nil {
+		return nil, err
+	}
+	var entries []utils.GoSumEntry
+	for _, entry := range goSumEntries {
+		if entry.IsGoModOnly() {
+			logger.Debug(fmt.Sprintf("Skipping %s@%s, which has only a go.mod hash in %s", entry.Path, entry.Version, goSumPath))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if skipped := len(goSumEntries) - len(entries); skipped > 0 {
+		logger.Info(fmt.Sprintf("%d module versions in %s have only a go.mod hash, and are skipped", skipped, goSumPath))
+	}
+
+	// Find the versions which weren't seeded yet to each of the deployers.
+	pending := map[string]bool{}
+	targetCaches := make([]*cache.DependenciesCache, len(deployers))
+	for i, deployer := range deployers {
+		target := GetDeployerTargetName(deployer)
+		targetCaches[i] = dependenciesCache.GetTarget(target)
+		targetClient, err := utils.NewProxyClient(deployer.ServiceManager().GetConfig().GetServiceDetails(), deployer.Repo())
+		if err != nil {
+			return nil, err
+		}
+		missing := 0
+		for _, entry := range entries {
+			exists, err := targetClient.Exists(entry.Path, entry.Version)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				targetCaches[i].GetMap()[getSeedPackageId(entry)] = true
+			} else {
+				pending[entry.Path+"@"+entry.Version] = true
+				missing++
+			}
+		}
+		// The versions which exist in the repository are recorded as skipped, so they are counted in the total too.
+		targetCaches[i].IncrementTotal(len(entries))
+		logger.Info(fmt.Sprintf("%d out of %d module versions in %s are missing from %s", missing, len(entries), goSumPath, target))
+	}
+
+	var resolverDetails auth.ServiceDetails
+	var resolverRepo string
+	if resolver := resolverDeployer.Resolver(); resolver != nil && !resolver.IsEmpty() {
+		resolverDetails, resolverRepo = resolver.ServiceManager().GetConfig().GetServiceDetails(), resolver.Repo()
+	}
+	resolutions, err := DownloadDependenciesWithFallback(pending, cachePath, resolverDetails, resolverRepo, noFallback, options...)
+	if _, ok := err.(*UnresolvedDependenciesError); err != nil && !ok {
+		return nil, err
+	}
+	resolutionsMap := map[string]ModuleResolution{}
+	for _, resolution := range resolutions {
+		resolutionsMap[resolution.Path+"@"+resolution.Version] = resolution
+	}
+
+	for _, entry := range entries {
+		for i, deployer := range deployers {
+			err = seedModule(entry, cachePath, deployer, targetCaches[i], resolutionsMap[entry.Path+"@"+entry.Version], opts)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed seeding %s@%s to %s: %s", entry.Path, entry.Version, GetDeployerTargetName(deployer), err.Error()))
+			}
+		}
+	}
+	report := dependenciesCache.GetReport()
+	if report.Failed > 0 {
+		return report, errorutils.CheckError(errors.New(fmt.Sprintf("%d out of %d module versions failed to be seeded", report.Failed, report.Total)))
+	}
+	return report, nil
+}
+
+// Verifies the checksums of the module version in the cache, and publishes it. Versions which were seeded before are recorded as skipped.
+func seedModule(entry utils.GoSumEntry, cachePath string, deployer *params.Params, dependenciesCache *cache.DependenciesCache, resolution ModuleResolution, opts *utils.Options) error {
+	id := getSeedPackageId(entry)
+	dependencyPackage := &Package{id: id, version: goModEncode(entry.Version)}
+	targetUrl := dependencyPackage.getTargetUrl(deployer.Repo(), deployer.ServiceManager())
+	if dependenciesCache.GetMap()[id] {
+		dependenciesCache.AddSkipped(id, targetUrl)
+		return nil
+	}
+	err := resolution.Err
+	if err == nil {
+		dependencyPackage, err = getVerifiedPackage(entry, cachePath, resolution.Source, opts)
+	}
+	if err != nil {
+		dependenciesCache.IncrementFailures()
+		dependenciesCache.AddFailed(id, targetUrl, err, 0)
+		return err
+	}
+	err = dependencyPackage.PopulateModAndPublish(deployer.Repo(), dependenciesCache, deployer.ServiceManager())
+	if err != nil {
+		return err
+	}
+	dependenciesCache.GetMap()[id] = true
+	return nil
+}
+
+// Returns the package of the module version in the cache, after verifying the hashes of its files against the go.sum entry.
+// The source is the source the module version was downloaded from.
+// The entry must include the hash of the zip, so that a zip is never published without being verified.
+func getVerifiedPackage(entry utils.GoSumEntry, cachePath string, source utils.Source, opts *utils.Options) (*Package, error) {
+	if entry.ZipHash == "" {
+		return nil, errorutils.CheckError(errors.New("the hash of the module zip is missing from go.sum"))
+	}
+	dependencyPackage, err := createDependency(cachePath, goModEncode(entry.Path), goModEncode(entry.Version), opts)
+	if err != nil {
+		return nil, err
+	}
+	if dependencyPackage == nil {
+		return nil, errorutils.CheckError(errors.New("the module zip is missing from the cache"))
+	}
+	zipHash, goModHash, err := hashModuleFiles(dependencyPackage)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyHashes(entry.Path, entry.Version, source, entry.ZipHash, entry.GoModHash, zipHash, goModHash)
+	if err != nil {
+		return nil, err
+	}
+	return dependencyPackage, nil
+}
+
+func getSeedPackageId(entry utils.GoSumEntry) string {
+	return goModEncode(entry.Path) + ":" + goModEncode(entry.Version)
+}
@@ -0,0 +1,97 @@
+package executers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/params"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedFromGoSum(t *testing.T) {
+	server := createArtifactoryTestServer(t)
+	defer server.Close()
+	// The go command refuses to send credentials to an http server.
+	server.SetCredentials("", "")
+	assert.NoError(t, server.AddRepo("go-virtual"))
+	goMod := "module example.com/hello\n"
+	createTestModuleZip(t, server.RepoDir("go-virtual"), "example.com/hello", "v1.0.0", map[string]string{"go.mod": goMod, "hello.go": "package hello\n"})
+	createTestModuleZip(t, server.RepoDir("go-virtual"), "example.com/bad", "v1.0.0", map[string]string{"go.mod": "module example.com/bad\n"})
+	zipHash, err := utils.HashZip(filepath.Join(server.RepoDir("go-virtual"), "example.com", "hello", "@v", "v1.0.0.zip"))
+	assert.NoError(t, err)
+	goModHash, err := utils.HashGoMod([]byte(goMod))
+	assert.NoError(t, err)
+
+	tempDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer fileutils.RemoveTempDir(tempDir)
+	modCache := filepath.Join(tempDir, "modcache")
+	for key, value := range map[string]string{"GOMODCACHE": modCache, "GONOSUMDB": "example.com,example.invalid", "GOFLAGS": "-modcacherw"} {
+		defer os.Setenv(key, os.Getenv(key))
+		assert.NoError(t, os.Setenv(key, value))
+	}
+	goSumPath := filepath.Join(tempDir, "go.sum")
+	goSum := "example.com/bad v1.0.0 h1:wrong=\n" +
+		"example.com/hello v1.0.0 " + zipHash + "\n" +
+		"example.com/hello v1.0.0/go.mod " + goModHash + "\n" +
+		"example.invalid/missing v1.0.0/go.mod h1:missing=\n"
+	assert.NoError(t, ioutil.WriteFile(goSumPath, []byte(goSum), 0644))
+
+	servicesManager, err := server.ServicesManager()
+	assert.NoError(t, err)
+	resolverDeployer := &params.ResolverDeployer{}
+	resolverDeployer.SetResolver((&params.Params{}).SetRepo("go-virtual").SetServiceManager(servicesManager))
+	resolverDeployer.SetDeployer((&params.Params{}).SetRepo("go-local").SetServiceManager(servicesManager))
+	cachePath := filepath.Join(modCache, "cache", "download")
+	target := GetDeployerTargetName(resolverDeployer.Deployer())
+	report, err := SeedFromGoSum(goSumPath, cachePath, resolverDeployer, true, nil)
+	assert.Error(t, err)
+	// The version which has only a go.mod hash is skipped.
+	if assert.NotNil(t, report) && assert.Contains(t, report.Targets, target) && assert.Len(t, report.Targets[target].Modules, 2) {
+		modules := report.Targets[target].Modules
+		assert.Equal(t, 1, report.Published)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, cache.Failed, modules[0].Outcome)
+		assert.Contains(t, modules[0].Error, "Checksum mismatch")
+		assert.Equal(t, cache.PublishRecord{Id: "example.com/hello:v1.0.0", Outcome: cache.Published}, cache.PublishRecord{Id: modules[1].Id, Outcome: modules[1].Outcome})
+	}
+	assert.Len(t, server.Uploads(), 3)
+	for _, upload := range server.Uploads() {
+		assert.Equal(t, "go-local", upload.Repo)
+		assert.Equal(t, "example.com/hello", upload.Module)
+	}
+
+	// Seeding again skips the versions which were already seeded.
+	uploads := len(server.Uploads())
+	report, err = SeedFromGoSum(goSumPath, cachePath, resolverDeployer, true, nil)
+	assert.Error(t, err)
+	if assert.NotNil(t, report) {
+		assert.Equal(t, 1, report.Skipped)
+		assert.Equal(t, 0, report.Published)
+		assert.Equal(t, report.Total, report.Published+report.Skipped+report.Failed)
+	}
+	assert.Len(t, server.Uploads(), uploads)
+
+	// The versions are seeded to each of the deployers which is missing them.
+	dr := createArtifactoryTestServer(t)
+	defer dr.Close()
+	drManager, err := dr.ServicesManager()
+	assert.NoError(t, err)
+	resolverDeployer.AddDeployer((&params.Params{}).SetRepo("go-local").SetServiceManager(drManager))
+	report, err = SeedFromGoSum(goSumPath, cachePath, resolverDeployer, true, nil)
+	assert.Error(t, err)
+	if assert.NotNil(t, report) {
+		assert.Equal(t, 1, report.Targets[target].Skipped)
+		assert.Equal(t, 1, report.Targets[GetDeployerTargetName(resolverDeployer.Deployers()[1])].Published)
+		for _, targetReport := range report.Targets {
+			assert.Equal(t, targetReport.Total, targetReport.Published+targetReport.Skipped+targetReport.Failed)
+		}
+	}
+	assert.Len(t, server.Uploads(), uploads)
+	assert.Len(t, dr.Uploads(), 3)
+}
@@ -0,0 +1,10 @@
+module example.com/vendorproject
+
+go 1.16
+
+require (
+	github.com/jfrog/lib v1.0.0
+	rsc.io/quote v1.5.2
+)
+
+replace rsc.io/quote => github.com/Forked/quote v1.5.3
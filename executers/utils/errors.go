@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	FailedToRetrieve          = "Failed to retrieve"
+	FromBothArtifactoryAndVcs = "from both Artifactory and VCS"
+)
+
+// The source a module was retrieved from.
+type Source string
+
+const (
+	ArtifactorySource Source = "artifactory"
+	VcsSource         Source = "vcs"
+	// The module already existed in the local module cache.
+	CacheSource Source = "cache"
+)
+
+// Returned when Artifactory rejects the credentials (401), or the user isn't permitted to perform the request (403).
+type AuthenticationError struct {
+	Module     string
+	Version    string
+	Url        string
+	StatusCode int
+}
+
+func (e *AuthenticationError) Error() string {
+	target := "Artifactory"
+	if e.Module != "" {
+		target = moduleVersionString(e.Module, e.Version) + " from Artifactory"
+	}
+	if e.Url != "" {
+		target += " (" + e.Url + ")"
+	}
+	return fmt.Sprintf("Authentication failed while accessing %s: %d %s", target, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Returned when the module couldn't be retrieved directly from its VCS, after falling back from Artifactory.
+type VcsFallbackError struct {
+	Module  string
+	Version string
+	Err     error
+}
+
+func (e *VcsFallbackError) Error() string {
+	return fmt.Sprintf("%s %s from VCS: %s", FailedToRetrieve, moduleVersionString(e.Module, e.Version), e.Err)
+}
+
+func (e *VcsFallbackError) Unwrap() error {
+	return e.Err
+}
+
+// Returned when the module couldn't be retrieved from Artifactory, nor directly from its VCS.
+// Unwrap returns the Artifactory error, or the VCS error if the Artifactory error is unknown. The VCS error is a *VcsFallbackError.
+type BothSourcesError struct {
+	Module         string
+	Version        string
+	ArtifactoryErr error
+	VcsErr         error
+}
+
+func (e *BothSourcesError) Error() string {
+	message := fmt.Sprintf("%s %s %s", FailedToRetrieve, moduleVersionString(e.Module, e.Version), FromBothArtifactoryAndVcs)
+	if e.ArtifactoryErr != nil {
+		message += fmt.Sprintf("\nArtifactory: %s", e.ArtifactoryErr)
+	}
+	if e.VcsErr != nil {
+		message += fmt.Sprintf("\nVCS: %s", e.VcsErr)
+	}
+	return message
+}
+
+func (e *BothSourcesError) Unwrap() error {
+	if e.ArtifactoryErr == nil {
+		return e.VcsErr
+	}
+	return e.ArtifactoryErr
+}
+
+// Returned when the checksum of a module file doesn't match the expected checksum.
+// Source is empty if it's unknown where the file was retrieved from.
+type ChecksumMismatchError struct {
+	Module   string
+	Version  string
+	Source   Source
+	File     string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	target := moduleVersionString(e.Module, e.Version)
+	if e.File != "" {
+		target += " (" + e.File + ")"
+	}
+	if e.Source != "" {
+		target += " retrieved from " + string(e.Source)
+	}
+	message := "Checksum mismatch for " + target
+	if e.Expected != "" || e.Actual != "" {
+		message += fmt.Sprintf(": expected %s, actual %s", e.Expected, e.Actual)
+	}
+	return message
+}
+
+// Returns the typed error matching the status code of a failed request for the module, or nil if there is no such type.
+func NewStatusError(statusCode int, modulePath, version, url string) error {
+	switch statusCode {
+	case http.StatusNotFound:
+		return &ModuleNotFoundError{Module: modulePath, Version: version, Url: url}
+	case http.StatusGone:
+		return &ModuleGoneError{Module: modulePath, Version: version, Url: url}
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &AuthenticationError{Module: modulePath, Version: version, Url: url, StatusCode: statusCode}
+	}
+	return nil
+}
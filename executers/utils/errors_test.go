@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStatusError(t *testing.T) {
+	var notFoundErr *ModuleNotFoundError
+	assert.True(t, errors.As(NewStatusError(http.StatusNotFound, "rsc.io/quote", "v1.5.2", "url"), &notFoundErr))
+	var goneErr *ModuleGoneError
+	assert.True(t, errors.As(NewStatusError(http.StatusGone, "rsc.io/quote", "v1.5.2", "url"), &goneErr))
+	var authErr *AuthenticationError
+	if assert.True(t, errors.As(NewStatusError(http.StatusForbidden, "rsc.io/quote", "v1.5.2", "url"), &authErr)) {
+		assert.Equal(t, "Authentication failed while accessing rsc.io/quote@v1.5.2 from Artifactory (url): 403 Forbidden", authErr.Error())
+	}
+	assert.Nil(t, NewStatusError(http.StatusInternalServerError, "rsc.io/quote", "v1.5.2", "url"))
+}
+
+func TestBothSourcesError(t *testing.T) {
+	artifactoryErr := &ModuleNotFoundError{Module: "rsc.io/quote", Version: "v1.5.2", Url: "url"}
+	vcsErr := &VcsFallbackError{Module: "rsc.io/quote", Version: "v1.5.2", Err: errors.New("exit status 128")}
+	var err error = &BothSourcesError{Module: "rsc.io/quote", Version: "v1.5.2", ArtifactoryErr: artifactoryErr, VcsErr: vcsErr}
+	assert.Equal(t, "Failed to retrieve rsc.io/quote@v1.5.2 from both Artifactory and VCS\n"+
+		"Artifactory: rsc.io/quote@v1.5.2 was not found in Artifactory: url\n"+
+		"VCS: Failed to retrieve rsc.io/quote@v1.5.2 from VCS: exit status 128", err.Error())
+
+	// The Artifactory error is unwrapped.
+	var notFoundErr *ModuleNotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+	var bothErr *BothSourcesError
+	assert.True(t, errors.As(err, &bothErr))
+}
+
+func TestChecksumMismatchError(t *testing.T) {
+	err := &ChecksumMismatchError{Module: "rsc.io/quote", Version: "v1.5.2", Source: ArtifactorySource, File: "zip", Expected: "h1:a=", Actual: "h1:b="}
+	assert.Equal(t, "Checksum mismatch for rsc.io/quote@v1.5.2 (zip) retrieved from artifactory: expected h1:a=, actual h1:b=", err.Error())
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"io/ioutil"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// The parsed content of a go.mod file.
+type GoMod struct {
+	// The module path.
+	Module string
+	// The deprecation message of the module, taken from a '// Deprecated:' comment on the module directive.
+	Deprecated string
+	// The version in the go directive, for example 1.17.
+	Go string
+	// The toolchain in the toolchain directive, for example go1.21.0.
+	Toolchain string
+	Require   []GoModRequire
+	Replace   []GoModReplace
+	Exclude   []GoModVersion
+	Retract   []GoModRetract
+}
+
+// A module path and version.
+type GoModVersion struct {
+	Path string
+	// Empty for a replacement by a local directory, or for a replace directive which applies to all versions.
+	Version string
+}
+
+type GoModRequire struct {
+	GoModVersion
+	// True if the requirement is marked by an '// indirect' comment.
+	Indirect bool
+}
+
+type GoModReplace struct {
+	Old GoModVersion
+	New GoModVersion
+}
+
+// Returns true if the module is replaced by a local directory.
+func (replace *GoModReplace) IsLocal() bool {
+	return replace.New.Version == ""
+}
+
+// A range of retracted versions. Both bounds are included. If a single version is retracted, Low is equal to High.
+type GoModRetract struct {
+	Low       string
+	High      string
+	Rationale string
+}
+
+// Returns true if the version is in the retracted range.
+func (retract *GoModRetract) Contains(version string) bool {
+	return semver.Compare(retract.Low, version) <= 0 && semver.Compare(version, retract.High) <= 0
+}
+
+// Reads and parses the go.mod file at the provided path.
+func ReadGoMod(path string) (*GoMod, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return ParseGoMod(path, content)
+}
+
+// Parses the content of a go.mod file. The file name is used in error messages only.
+// Versions are not required to be canonical, so go.mod files which were not yet processed by the go command are supported.
+func ParseGoMod(fileName string, content []byte) (*GoMod, error) {
+	file, err := modfile.Parse(fileName, content, keepVersion)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	goMod := &GoMod{}
+	if file.Module != nil {
+		goMod.Module = file.Module.Mod.Path
+		goMod.Deprecated = file.Module.Deprecated
+	}
+	if file.Go != nil {
+		goMod.Go = file.Go.Version
+	}
+	if file.Toolchain != nil {
+		goMod.Toolchain = file.Toolchain.Name
+	}
+	for _, require := range file.Require {
+		goMod.Require = append(goMod.Require, GoModRequire{GoModVersion: GoModVersion{Path: require.Mod.Path, Version: require.Mod.Version}, Indirect: require.Indirect})
+	}
+	for _, replace := range file.Replace {
+		goMod.Replace = append(goMod.Replace, GoModReplace{
+			Old: GoModVersion{Path: replace.Old.Path, Version: replace.Old.Version},
+			New: GoModVersion{Path: replace.New.Path, Version: replace.New.Version},
+		})
+	}
+	for _, exclude := range file.Exclude {
+		goMod.Exclude = append(goMod.Exclude, GoModVersion{Path: exclude.Mod.Path, Version: exclude.Mod.Version})
+	}
+	for _, retract := range file.Retract {
+		goMod.Retract = append(goMod.Retract, GoModRetract{Low: retract.Low, High: retract.High, Rationale: retract.Rationale})
+	}
+	return goMod, nil
+}
+
+func keepVersion(_, version string) (string, error) {
+	return version, nil
+}
+
+// Returns true if the go.mod file includes at least one require directive.
+func (goMod *GoMod) HasRequirements() bool {
+	return len(goMod.Require) > 0
+}
+
+// Returns the requirements which are not marked as indirect.
+func (goMod *GoMod) DirectRequirements() []GoModRequire {
+	return goMod.filterRequirements(false)
+}
+
+// Returns the requirements which are marked as indirect.
+func (goMod *GoMod) IndirectRequirements() []GoModRequire {
+	return goMod.filterRequirements(true)
+}
+
+func (goMod *GoMod) filterRequirements(indirect bool) []GoModRequire {
+	var requirements []GoModRequire
+	for _, require := range goMod.Require {
+		if require.Indirect == indirect {
+			requirements = append(requirements, require)
+		}
+	}
+	return requirements
+}
+
+// Returns the replacement which applies to the provided module version, or nil if it is not replaced.
+// A replacement of the specific version takes precedence over a replacement of all the versions of the module.
+func (goMod *GoMod) GetReplacement(path, version string) *GoModReplace {
+	var allVersions *GoModReplace
+	for i := range goMod.Replace {
+		if goMod.Replace[i].Old.Path != path {
+			continue
+		}
+		if goMod.Replace[i].Old.Version == version {
+			return &goMod.Replace[i]
+		}
+		if goMod.Replace[i].Old.Version == "" {
+			allVersions = &goMod.Replace[i]
+		}
+	}
+	return allVersions
+}
+
+// Returns the retraction which includes the provided version, or nil if the version is not retracted.
+func (goMod *GoMod) GetRetraction(version string) *GoModRetract {
+	for i := range goMod.Retract {
+		if goMod.Retract[i].Contains(version) {
+			return &goMod.Retract[i]
+		}
+	}
+	return nil
+}
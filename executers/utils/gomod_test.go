@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadGoModReplace(t *testing.T) {
+	expectedRequire := []GoModRequire{
+		{GoModVersion: GoModVersion{Path: "code.cloudfoundry.org/clock", Version: "v0.0.0-20180518195852-02e53af36e6c"}, Indirect: true},
+		{GoModVersion: GoModVersion{Path: "contrib.go.opencensus.io/exporter/ocagent", Version: "v0.4.6"}, Indirect: true},
+	}
+	for _, fileName := range []string{"replaceBlockFirst.txt", "replaceBlockLast.txt", "replaceLineFirst.txt", "replaceLineLast.txt", "replaceBothBlockFirst.txt", "replaceBothLineFirst.txt"} {
+		t.Run(fileName, func(t *testing.T) {
+			goMod, err := ReadGoMod(filepath.Join("..", "..", "testdata", "mods", fileName))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, "jfrog.com/jfrog-router", goMod.Module)
+			assert.True(t, goMod.HasRequirements())
+			assert.Equal(t, expectedRequire, goMod.Require)
+			assert.Equal(t, expectedRequire, goMod.IndirectRequirements())
+			assert.Empty(t, goMod.DirectRequirements())
+			replace := goMod.GetReplacement("github.com/Masterminds/sprig", "v2.12.0+incompatible")
+			if assert.NotNil(t, replace) {
+				assert.Equal(t, GoModVersion{Path: "github.com/Masterminds/sprig", Version: "v2.13.0+incompatible"}, replace.New)
+				assert.False(t, replace.IsLocal())
+			}
+			assert.Nil(t, goMod.GetReplacement("code.cloudfoundry.org/clock", "v0.0.0-20180518195852-02e53af36e6c"))
+		})
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	content := `// Deprecated: use example.com/new instead.
+module example.com/old
+
+go 1.21
+
+toolchain go1.21.3
+
+require (
+	// A comment before the requirement.
+	github.com/jfrog/gofrog v1.1.0
+	golang.org/x/text v0.3.3 // indirect
+)
+
+require rsc.io/quote v1.5.2 // indirectly required by nothing
+
+exclude rsc.io/sampler v1.99.99
+
+replace (
+	rsc.io/quote v1.5.2 => ../quote
+	rsc.io/quote => rsc.io/quote v1.5.1
+)
+
+retract [v1.0.0, v1.0.5] // Published accidentally.
+retract v1.1.0
+`
+	goMod, err := ParseGoMod("go.mod", []byte(content))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "example.com/old", goMod.Module)
+	assert.Equal(t, "use example.com/new instead.", goMod.Deprecated)
+	assert.Equal(t, "1.21", goMod.Go)
+	assert.Equal(t, "go1.21.3", goMod.Toolchain)
+	assert.Equal(t, []GoModRequire{
+		{GoModVersion: GoModVersion{Path: "github.com/jfrog/gofrog", Version: "v1.1.0"}},
+		{GoModVersion: GoModVersion{Path: "golang.org/x/text", Version: "v0.3.3"}, Indirect: true},
+		{GoModVersion: GoModVersion{Path: "rsc.io/quote", Version: "v1.5.2"}},
+	}, goMod.Require)
+	assert.Len(t, goMod.DirectRequirements(), 2)
+	assert.Equal(t, []GoModVersion{{Path: "rsc.io/sampler", Version: "v1.99.99"}}, goMod.Exclude)
+
+	// A replacement of a specific version takes precedence.
+	replace := goMod.GetReplacement("rsc.io/quote", "v1.5.2")
+	if assert.NotNil(t, replace) {
+		assert.True(t, replace.IsLocal())
+		assert.Equal(t, "../quote", replace.New.Path)
+	}
+	replace = goMod.GetReplacement("rsc.io/quote", "v1.5.0")
+	if assert.NotNil(t, replace) {
+		assert.Equal(t, "v1.5.1", replace.New.Version)
+	}
+
+	assert.Equal(t, []GoModRetract{{Low: "v1.0.0", High: "v1.0.5", Rationale: "Published accidentally."}, {Low: "v1.1.0", High: "v1.1.0"}}, goMod.Retract)
+	for version, retracted := range map[string]bool{"v0.9.0": false, "v1.0.0": true, "v1.0.3": true, "v1.0.5": true, "v1.0.6": false, "v1.1.0": true} {
+		assert.Equal(t, retracted, goMod.GetRetraction(version) != nil, version)
+	}
+}
+
+func TestParseGoModInvalid(t *testing.T) {
+	_, err := ParseGoMod("go.mod", []byte("module example.com/a\nrequire (\n"))
+	assert.Error(t, err)
+	_, err = ParseGoMod("go.mod", []byte("module example.com/a\nunknown example.com/b\n"))
+	assert.Error(t, err)
+}
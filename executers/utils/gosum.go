@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// The checksums of a module version, as recorded in a go.sum file.
+type GoSumEntry struct {
+	Path    string
+	Version string
+	// The h1 hash of the module zip. Empty if only the go.mod file of the version is recorded.
+	ZipHash string
+	// The h1 hash of the go.mod file.
+	GoModHash string
+}
+
+// Returns true if only the go.mod file of the version is recorded, which is the case for versions whose packages aren't built.
+func (entry *GoSumEntry) IsGoModOnly() bool {
+	return entry.ZipHash == "" && entry.GoModHash != ""
+}
+
+// Parses the content of a go.sum file. Returns an entry per module version, sorted by the module path and version.
+func ParseGoSum(content []byte) ([]GoSumEntry, error) {
+	entries := map[string]*GoSumEntry{}
+	for i, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, errorutils.CheckError(errors.New(fmt.Sprintf("Malformed go.sum line %d: %s", i+1, line)))
+		}
+		version := strings.TrimSuffix(fields[1], "/go.mod")
+		key := fields[0] + "@" + version
+		entry, ok := entries[key]
+		if !ok {
+			entry = &GoSumEntry{Path: fields[0], Version: version}
+			entries[key] = entry
+		}
+		if version == fields[1] {
+			entry.ZipHash = fields[2]
+		} else {
+			entry.GoModHash = fields[2]
+		}
+	}
+	var result []GoSumEntry
+	for _, entry := range entries {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// Reads and parses a go.sum file.
+func ReadGoSum(goSumPath string) ([]GoSumEntry, error) {
+	content, err := ioutil.ReadFile(goSumPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return ParseGoSum(content)
+}
+
+// Returns the h1 hash of a module zip, as recorded in go.sum.
+func HashZip(zipPath string) (string, error) {
+	hash, err := dirhash.HashZip(zipPath, dirhash.Hash1)
+	return hash, errorutils.CheckError(err)
+}
+
+// Returns the h1 hash of a go.mod file, as recorded in go.sum.
+func HashGoMod(content []byte) (string, error) {
+	hash, err := dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(content)), nil
+	})
+	return hash, errorutils.CheckError(err)
+}
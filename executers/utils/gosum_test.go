@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGoSum(t *testing.T) {
+	content := `golang.org/x/text v0.0.0-20170915032832-14c0d48ead0c h1:qgOY6WgZOaTkIIMiVjBQcw93ERBE4m30iBm00nkL0i8=
+golang.org/x/text v0.0.0-20170915032832-14c0d48ead0c/go.mod h1:NqM8EUOU14njkJ3fqMW+pc6Ldnwhi/IjpwHt7yyuwOQ=
+rsc.io/quote v1.5.2 h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=
+rsc.io/quote v1.5.2/go.mod h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=
+
+rsc.io/sampler v1.3.0/go.mod h1:T1hPZKmBbMNahiBKFy5HrXp6adAjACjK9JXDnKaTXpA=
+`
+	entries, err := ParseGoSum([]byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, []GoSumEntry{
+		{Path: "golang.org/x/text", Version: "v0.0.0-20170915032832-14c0d48ead0c", ZipHash: "h1:qgOY6WgZOaTkIIMiVjBQcw93ERBE4m30iBm00nkL0i8=", GoModHash: "h1:NqM8EUOU14njkJ3fqMW+pc6Ldnwhi/IjpwHt7yyuwOQ="},
+		{Path: "rsc.io/quote", Version: "v1.5.2", ZipHash: "h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=", GoModHash: "h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0="},
+		{Path: "rsc.io/sampler", Version: "v1.3.0", GoModHash: "h1:T1hPZKmBbMNahiBKFy5HrXp6adAjACjK9JXDnKaTXpA="},
+	}, entries)
+
+	_, err = ParseGoSum([]byte("rsc.io/quote v1.5.2\n"))
+	assert.Error(t, err)
+}
+
+func TestHashGoMod(t *testing.T) {
+	// The go.mod of rsc.io/quote v1.5.2, and its hash in go.sum.
+	hash, err := HashGoMod([]byte("module \"rsc.io/quote\"\n\nrequire \"rsc.io/sampler\" v1.3.0\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=", hash)
+}
+
+func TestHashZip(t *testing.T) {
+	zipPath := filepath.Join("..", "..", "testdata", "zip", "rsc.io", "quote", "@v", "v1.5.2.zip")
+	hash, err := HashZip(zipPath)
+	assert.NoError(t, err)
+	assert.Regexp(t, "^h1:", hash)
+
+	_, err = HashZip(filepath.Join("testdata", "missing.zip"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Fields added to the messages of a logger, such as the module id, the repository or a run id.
+type LogFields map[string]string
+
+// Implemented by structured loggers, which can keep the fields separate from the messages.
+// Loggers which don't implement it are wrapped by a FieldsLogger, which adds the fields as a prefix of each message.
+type FieldsLog interface {
+	log.Log
+	WithFields(fields LogFields) log.Log
+}
+
+// Returns a logger which delegates to the global jfrog-client-go logger at the time of each call,
+// so it follows changes made by log.SetLogger.
+func GetGlobalLogger() log.Log {
+	return globalLogger{}
+}
+
+type globalLogger struct{}
+
+func (globalLogger) Debug(a ...interface{}) {
+	log.Debug(a...)
+}
+
+func (globalLogger) Info(a ...interface{}) {
+	log.Info(a...)
+}
+
+func (globalLogger) Warn(a ...interface{}) {
+	log.Warn(a...)
+}
+
+func (globalLogger) Error(a ...interface{}) {
+	log.Error(a...)
+}
+
+func (globalLogger) Output(a ...interface{}) {
+	log.Output(a...)
+}
+
+// Returns a logger which adds the fields to the messages of the provided logger.
+// If the logger is nil, the global logger is used.
+func WithLogFields(logger log.Log, fields LogFields) log.Log {
+	if logger == nil {
+		logger = GetGlobalLogger()
+	}
+	if fieldsLog, ok := logger.(FieldsLog); ok {
+		return fieldsLog.WithFields(fields)
+	}
+	return &FieldsLogger{logger: logger, fields: fields}
+}
+
+// FieldsLogger adds fields to the messages of another logger, in the format '[key1=value1 key2=value2] message'.
+type FieldsLogger struct {
+	logger log.Log
+	fields LogFields
+}
+
+// Returns a logger with the fields of this logger and the provided fields.
+// Fields which already exist are overridden.
+func (fieldsLogger *FieldsLogger) WithFields(fields LogFields) log.Log {
+	merged := LogFields{}
+	for key, value := range fieldsLogger.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return &FieldsLogger{logger: fieldsLogger.logger, fields: merged}
+}
+
+func (fieldsLogger *FieldsLogger) GetFields() LogFields {
+	return fieldsLogger.fields
+}
+
+func (fieldsLogger *FieldsLogger) Debug(a ...interface{}) {
+	fieldsLogger.logger.Debug(fieldsLogger.withPrefix(a)...)
+}
+
+func (fieldsLogger *FieldsLogger) Info(a ...interface{}) {
+	fieldsLogger.logger.Info(fieldsLogger.withPrefix(a)...)
+}
+
+func (fieldsLogger *FieldsLogger) Warn(a ...interface{}) {
+	fieldsLogger.logger.Warn(fieldsLogger.withPrefix(a)...)
+}
+
+func (fieldsLogger *FieldsLogger) Error(a ...interface{}) {
+	fieldsLogger.logger.Error(fieldsLogger.withPrefix(a)...)
+}
+
+// The output is not prefixed, since it is the result of a command rather than a log message.
+func (fieldsLogger *FieldsLogger) Output(a ...interface{}) {
+	fieldsLogger.logger.Output(a...)
+}
+
+func (fieldsLogger *FieldsLogger) withPrefix(a []interface{}) []interface{} {
+	if len(fieldsLogger.fields) == 0 {
+		return a
+	}
+	var keys []string
+	for key := range fieldsLogger.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var fields []string
+	for _, key := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%s", key, fieldsLogger.fields[key]))
+	}
+	return append([]interface{}{"[" + strings.Join(fields, " ") + "]"}, a...)
+}
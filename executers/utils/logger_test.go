@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// A structured logger, which records the fields separately from the messages.
+type structuredLogger struct {
+	log.Log
+	fields LogFields
+}
+
+func (logger *structuredLogger) WithFields(fields LogFields) log.Log {
+	return &structuredLogger{Log: logger.Log, fields: fields}
+}
+
+func TestFieldsLogger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	base := log.NewLogger(log.DEBUG, buffer)
+	logger := WithLogFields(base, LogFields{"repo": "go-local", "runId": "42"})
+	logger.Info("Publishing")
+	assert.Contains(t, buffer.String(), "[repo=go-local runId=42] Publishing")
+
+	buffer.Reset()
+	logger.(*FieldsLogger).WithFields(LogFields{"module": "rsc.io/quote:v1.5.2", "repo": "go-remote"}).Warn("Retrying")
+	assert.Contains(t, buffer.String(), "[module=rsc.io/quote:v1.5.2 repo=go-remote runId=42] Retrying")
+
+	// Structured loggers receive the fields.
+	structured := WithLogFields(&structuredLogger{Log: base}, LogFields{"repo": "go-local"})
+	assert.Equal(t, LogFields{"repo": "go-local"}, structured.(*structuredLogger).fields)
+}
+
+func TestGlobalLogger(t *testing.T) {
+	logger := GetGlobalLogger()
+	buffer := &bytes.Buffer{}
+	// The global logger is used at the time of the call.
+	log.SetLogger(log.NewLogger(log.INFO, buffer))
+	defer log.SetLogger(log.NewLogger(log.ERROR, nil))
+	WithLogFields(nil, LogFields{"runId": "1"}).Info("message")
+	logger.Debug("hidden")
+	assert.Contains(t, buffer.String(), "[runId=1] message")
+	assert.NotContains(t, buffer.String(), "hidden")
+}
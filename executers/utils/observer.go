@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type Operation string
+
+const (
+	PublishOperation  Operation = "publish"
+	DownloadOperation Operation = "download"
+	// Running the go command by RunGo.
+	GoOperation Operation = "go"
+)
+
+// Describes a step of an operation on a module.
+type ProgressEvent struct {
+	Operation Operation
+	// The module id, such as github.com/jfrog/gocmd:v1.0.0. For the go operation, the arguments of the go command.
+	ModuleId string
+	// The repository which the module is published to or downloaded from. Empty if the module is downloaded by the go command.
+	Repo string
+	// The position of the module in the operation, such as 3/400. May be empty.
+	Progress string
+	// The number of bytes transferred so far. Set in BytesTransferred events, and in the ModuleFinished events of publishing and of downloading from Artifactory.
+	Bytes int64
+	// The number of the attempt which is about to start. Set in ModuleRetried events.
+	Attempt int
+	// Set in ModuleFailed and ModuleRetried events.
+	Err error
+}
+
+// Observer receives the progress of long-running operations, such as publishing and downloading modules.
+// The events of an operation on a module start with ModuleStarted, and end with either ModuleFinished or ModuleFailed.
+// ModuleRetried is sent when an operation is retried from another source, such as downloading directly from VCS after Artifactory failed.
+// The retries of the HTTP client, for example while publishing, are internal to jfrog-client-go and aren't reported.
+type Observer interface {
+	ModuleStarted(event ProgressEvent)
+	// Sent while the files of the module are downloaded from Artifactory, as the bytes are read.
+	// Publishing reports a single event after the files are uploaded, since jfrog-client-go doesn't report the progress of the upload.
+	BytesTransferred(event ProgressEvent)
+	ModuleFinished(event ProgressEvent)
+	ModuleFailed(event ProgressEvent)
+	ModuleRetried(event ProgressEvent)
+}
+
+// The default observer, which logs the progress.
+type LogObserver struct {
+	// If nil, the global jfrog-client-go logger is used.
+	Logger log.Log
+}
+
+func (observer *LogObserver) getLogger() log.Log {
+	if observer.Logger != nil {
+		return observer.Logger
+	}
+	return GetGlobalLogger()
+}
+
+func (observer *LogObserver) ModuleStarted(event ProgressEvent) {
+	if event.Operation != PublishOperation {
+		return
+	}
+	message := fmt.Sprintf("Publishing: %s to %s", event.ModuleId, event.Repo)
+	if event.Progress != "" {
+		message += ":" + event.Progress
+	}
+	observer.getLogger().Info(message)
+}
+
+func (observer *LogObserver) BytesTransferred(event ProgressEvent) {}
+
+func (observer *LogObserver) ModuleFinished(event ProgressEvent) {}
+
+func (observer *LogObserver) ModuleFailed(event ProgressEvent) {}
+
+func (observer *LogObserver) ModuleRetried(event ProgressEvent) {
+	observer.getLogger().Debug(fmt.Sprintf("Retrying to %s %s, attempt %d: %s", event.Operation, event.ModuleId, event.Attempt, event.Err))
+}
+
+// Sends ModuleFinished to the observer, or ModuleFailed if err is not nil.
+func NotifyFinished(observer Observer, event ProgressEvent, err error) {
+	if err != nil {
+		event.Err = err
+		observer.ModuleFailed(event)
+		return
+	}
+	observer.ModuleFinished(event)
+}
+
+// A reader which sends a BytesTransferred event to the observer after each read, with the number of bytes read so far.
+type ProgressReader struct {
+	reader   io.Reader
+	observer Observer
+	event    ProgressEvent
+}
+
+// Returns a reader which reports the bytes read from reader to the observer. The event is sent with its Bytes field set.
+func NewProgressReader(reader io.Reader, observer Observer, event ProgressEvent) *ProgressReader {
+	return &ProgressReader{reader: reader, observer: observer, event: event}
+}
+
+func (progressReader *ProgressReader) Read(p []byte) (int, error) {
+	n, err := progressReader.reader.Read(p)
+	if n > 0 {
+		progressReader.event.Bytes += int64(n)
+		progressReader.observer.BytesTransferred(progressReader.event)
+	}
+	return n, err
+}
+
+// Returns the number of bytes read so far.
+func (progressReader *ProgressReader) GetBytes() int64 {
+	return progressReader.event.Bytes
+}
+
+var (
+	observer      Observer = &LogObserver{}
+	observerMutex sync.RWMutex
+)
+
+// Sets the observer which receives the progress events. If nil, the default LogObserver is set.
+func SetObserver(newObserver Observer) {
+	observerMutex.Lock()
+	defer observerMutex.Unlock()
+	if newObserver == nil {
+		newObserver = &LogObserver{}
+	}
+	observer = newObserver
+}
+
+func GetObserver() Observer {
+	observerMutex.RLock()
+	defer observerMutex.RUnlock()
+	return observer
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogObserver(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	log.SetLogger(log.NewLogger(log.INFO, buffer))
+	defer log.SetLogger(log.NewLogger(log.ERROR, nil))
+	SetObserver(nil)
+	observer := GetObserver()
+	event := ProgressEvent{Operation: PublishOperation, ModuleId: "rsc.io/quote:v1.5.2", Repo: "go-local", Progress: "3/400"}
+	observer.ModuleStarted(event)
+	observer.ModuleFinished(event)
+	observer.ModuleStarted(ProgressEvent{Operation: DownloadOperation, ModuleId: "rsc.io/quote@v1.5.2"})
+	observer.ModuleFailed(ProgressEvent{Operation: DownloadOperation, ModuleId: "rsc.io/quote@v1.5.2", Err: errors.New("failed")})
+	assert.Contains(t, buffer.String(), "Publishing: rsc.io/quote:v1.5.2 to go-local:3/400")
+	assert.NotContains(t, buffer.String(), "download")
+}
+
+type bytesObserver struct {
+	LogObserver
+	bytes []int64
+}
+
+func (observer *bytesObserver) BytesTransferred(event ProgressEvent) {
+	observer.bytes = append(observer.bytes, event.Bytes)
+}
+
+func TestProgressReader(t *testing.T) {
+	observer := &bytesObserver{}
+	reader := NewProgressReader(bytes.NewReader(make([]byte, 10)), observer, ProgressEvent{Operation: DownloadOperation, ModuleId: "rsc.io/quote:v1.5.2"})
+	buffer := make([]byte, 4)
+	for {
+		if _, err := reader.Read(buffer); err != nil {
+			break
+		}
+	}
+	assert.Equal(t, []int64{4, 8, 10}, observer.bytes)
+	assert.Equal(t, int64(10), reader.GetBytes())
+
+	content, err := ioutil.ReadAll(NewProgressReader(bytes.NewReader([]byte("content")), observer, ProgressEvent{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+}
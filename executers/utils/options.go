@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Options of the gocmd APIs, which are set by passing Option functions, such as WithLogger, to the APIs.
+// Without options, the global logger and observer are used.
+type Options struct {
+	// If nil, the global logger is used.
+	Logger log.Log
+	// If nil, a LogObserver is used if Logger is set, and the global observer otherwise.
+	Observer Observer
+	// Run on the dependencies before they are used, in the order they were added.
+	DependenciesChecks []DependenciesCheck
+	// If true, gocmd.Run downloads the dependencies in phases before running the go command. See WithPhasedDownloads.
+	PhasedDownloads bool
+}
+
+// Checks the dependencies before they are used, for example before they are added to the build-info or published.
+type DependenciesCheck interface {
+	// Returns an error if the dependencies, which are in the format returned by cmd.GetDependenciesList, fail the check.
+	CheckDependencies(dependencies map[string]bool) error
+}
+
+type Option func(options *Options)
+
+// Sets the logger used instead of the global logger. See WithLogFields for adding fields, such as a run id, to the logger.
+func WithLogger(logger log.Log) Option {
+	return func(options *Options) {
+		options.Logger = logger
+	}
+}
+
+// Sets the observer which receives the progress events, instead of the global observer.
+func WithObserver(observer Observer) Option {
+	return func(options *Options) {
+		options.Observer = observer
+	}
+}
+
+// Adds a check, which runs on the dependencies when they are listed by cmd.GetDependenciesList, and on each package before it is published.
+// Listing or publishing fails if the check fails.
+func WithDependenciesCheck(check DependenciesCheck) Option {
+	return func(options *Options) {
+		options.DependenciesChecks = append(options.DependenciesChecks, check)
+	}
+}
+
+// Makes gocmd.Run download the dependencies of the project before running the go command: first from Artifactory,
+// and then directly from VCS for the dependencies which Artifactory failed to provide, unless there is no fallback.
+// The observer receives the events of each dependency, and the dependencies which couldn't be downloaded are reported together.
+func WithPhasedDownloads() Option {
+	return func(options *Options) {
+		options.PhasedDownloads = true
+	}
+}
+
+// Returns the options set by the option functions.
+func NewOptions(options ...Option) *Options {
+	result := &Options{}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+func (options *Options) GetLogger() log.Log {
+	if options.Logger != nil {
+		return options.Logger
+	}
+	return GetGlobalLogger()
+}
+
+func (options *Options) GetObserver() Observer {
+	if options.Observer != nil {
+		return options.Observer
+	}
+	if options.Logger != nil {
+		return &LogObserver{Logger: options.Logger}
+	}
+	return GetObserver()
+}
+
+// Runs the dependencies checks, and returns the error of the first check which failed.
+func (options *Options) RunDependenciesChecks(dependencies map[string]bool) error {
+	for _, check := range options.DependenciesChecks {
+		if err := check.CheckDependencies(dependencies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	// Without options, the global logger and observer are used.
+	options := NewOptions()
+	assert.Equal(t, GetGlobalLogger(), options.GetLogger())
+	assert.Equal(t, GetObserver(), options.GetObserver())
+
+	// The progress is logged by the logger of the options.
+	buffer := &bytes.Buffer{}
+	logger := log.NewLogger(log.INFO, buffer)
+	options = NewOptions(WithLogger(logger))
+	assert.Equal(t, logger, options.GetLogger())
+	options.GetObserver().ModuleStarted(ProgressEvent{Operation: PublishOperation, ModuleId: "rsc.io/quote:v1.5.2"})
+	assert.Contains(t, buffer.String(), "rsc.io/quote:v1.5.2")
+
+	observer := &LogObserver{}
+	options = NewOptions(WithLogger(logger), WithObserver(observer))
+	assert.Equal(t, observer, options.GetObserver())
+}
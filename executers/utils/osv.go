@@ -0,0 +1,266 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/mod/semver"
+)
+
+const osvGoEcosystem = "Go"
+
+// A vulnerability entry in the OSV format (https://ossf.github.io/osv-schema).
+// Only the fields required for matching and reporting are included.
+type OsvEntry struct {
+	Id         string         `json:"id"`
+	Aliases    []string       `json:"aliases,omitempty"`
+	Summary    string         `json:"summary,omitempty"`
+	Details    string         `json:"details,omitempty"`
+	Published  string         `json:"published,omitempty"`
+	Modified   string         `json:"modified,omitempty"`
+	Withdrawn  string         `json:"withdrawn,omitempty"`
+	Affected   []OsvAffected  `json:"affected,omitempty"`
+	References []OsvReference `json:"references,omitempty"`
+}
+
+type OsvAffected struct {
+	Package OsvPackage `json:"package"`
+	Ranges  []OsvRange `json:"ranges,omitempty"`
+	// Versions which are affected in addition to the ranges.
+	Versions []string `json:"versions,omitempty"`
+}
+
+type OsvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type OsvRange struct {
+	Type   string     `json:"type"`
+	Events []OsvEvent `json:"events"`
+}
+
+// Each event includes one of the fields. The versions are semantic versions without the 'v' prefix, and "0" for the lowest version.
+type OsvEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+type OsvReference struct {
+	Type string `json:"type"`
+	Url  string `json:"url"`
+}
+
+// Returns the lowest version of the module which is newer than the provided version and fixes the entry,
+// or an empty string if there is no such fix.
+func (entry *OsvEntry) FixedVersion(modulePath, version string) string {
+	fixed := ""
+	for _, affected := range entry.Affected {
+		if affected.Package.Name != modulePath {
+			continue
+		}
+		for _, osvRange := range affected.Ranges {
+			for _, event := range osvRange.Events {
+				if event.Fixed == "" || semver.Compare(toSemver(event.Fixed), version) <= 0 {
+					continue
+				}
+				if fixed == "" || semver.Compare(toSemver(event.Fixed), fixed) < 0 {
+					fixed = toSemver(event.Fixed)
+				}
+			}
+		}
+	}
+	return fixed
+}
+
+// Returns true if the module version is affected by the entry.
+func (entry *OsvEntry) Affects(modulePath, version string) bool {
+	if entry.Withdrawn != "" {
+		return false
+	}
+	for _, affected := range entry.Affected {
+		if affected.Package.Name == modulePath && affected.isGo() && affected.affects(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the affected package is a Go module. The ecosystem is required by the OSV schema, so packages without one are ignored.
+func (affected *OsvAffected) isGo() bool {
+	return affected.Package.Ecosystem == osvGoEcosystem
+}
+
+func (affected *OsvAffected) affects(version string) bool {
+	for _, affectedVersion := range affected.Versions {
+		if semver.Compare(toSemver(affectedVersion), version) == 0 {
+			return true
+		}
+	}
+	for _, osvRange := range affected.Ranges {
+		if osvRange.Type == "SEMVER" && osvRange.contains(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the version is in the range, by applying the events in the order of their versions.
+// Build metadata, such as '+incompatible', is ignored when comparing versions.
+func (osvRange *OsvRange) contains(version string) bool {
+	events := append([]OsvEvent{}, osvRange.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return semver.Compare(events[i].version(), events[j].version()) < 0
+	})
+	affected := false
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" || semver.Compare(toSemver(event.Introduced), version) <= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if semver.Compare(toSemver(event.Fixed), version) <= 0 {
+				affected = false
+			}
+		case event.LastAffected != "":
+			if semver.Compare(toSemver(event.LastAffected), version) < 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+func (event *OsvEvent) version() string {
+	switch {
+	case event.Introduced == "0":
+		return "v0.0.0-0"
+	case event.Introduced != "":
+		return toSemver(event.Introduced)
+	case event.Fixed != "":
+		return toSemver(event.Fixed)
+	}
+	return toSemver(event.LastAffected)
+}
+
+// Adds the 'v' prefix used by Go to an OSV version.
+func toSemver(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+// An OSV database, indexed by the affected Go modules.
+type OsvDatabase struct {
+	entries map[string][]*OsvEntry
+}
+
+// Loads an OSV database from a directory or a zip, such as a snapshot of the Go vulnerability database or an OSV ecosystem export.
+// All the JSON files are read, and files which are not OSV entries, such as index files, are skipped.
+func LoadOsvDatabase(path string, options ...Option) (*OsvDatabase, error) {
+	logger := NewOptions(options...).GetLogger()
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	db := &OsvDatabase{entries: map[string][]*OsvEntry{}}
+	if stat.IsDir() {
+		err = db.loadDir(path, logger)
+	} else {
+		err = db.loadZip(path, logger)
+	}
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("Loaded the OSV database from", path)
+	return db, nil
+}
+
+func (db *OsvDatabase) loadDir(dir string, logger log.Log) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		db.add(path, content, logger)
+		return nil
+	})
+	return errorutils.CheckError(err)
+}
+
+func (db *OsvDatabase) loadZip(zipPath string, logger log.Log) error {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || filepath.Ext(file.Name) != ".json" {
+			continue
+		}
+		fileReader, err := file.Open()
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		content, err := ioutil.ReadAll(fileReader)
+		fileReader.Close()
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		db.add(file.Name, content, logger)
+	}
+	return nil
+}
+
+func (db *OsvDatabase) add(fileName string, content []byte, logger log.Log) {
+	entry := &OsvEntry{}
+	if err := json.Unmarshal(content, entry); err != nil || entry.Id == "" {
+		logger.Debug("Skipping a file which is not an OSV entry:", fileName)
+		return
+	}
+	modules := map[string]bool{}
+	for _, affected := range entry.Affected {
+		if affected.isGo() && !modules[affected.Package.Name] {
+			modules[affected.Package.Name] = true
+			db.entries[affected.Package.Name] = append(db.entries[affected.Package.Name], entry)
+		}
+	}
+}
+
+// Returns the number of Go entries in the database.
+func (db *OsvDatabase) Size() int {
+	ids := map[string]bool{}
+	for _, entries := range db.entries {
+		for _, entry := range entries {
+			ids[entry.Id] = true
+		}
+	}
+	return len(ids)
+}
+
+// Returns the entries which affect the module version, sorted by their IDs.
+func (db *OsvDatabase) Match(modulePath, version string) []*OsvEntry {
+	var matches []*OsvEntry
+	for _, entry := range db.entries[modulePath] {
+		if entry.Affects(modulePath, version) {
+			matches = append(matches, entry)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Id < matches[j].Id
+	})
+	return matches
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+var osvTestDir = filepath.Join("..", "..", "testdata", "osv")
+
+func TestLoadOsvDatabase(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	buffer := &bytes.Buffer{}
+	db, err := LoadOsvDatabase(osvTestDir, WithLogger(log.NewLogger(log.DEBUG, buffer)))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, db.Size())
+	assert.Contains(t, buffer.String(), "Loaded the OSV database from")
+
+	// Create a zip of the database, with the files at the root of the zip.
+	zipFile, err := ioutil.TempFile("", "osv*.zip")
+	assert.NoError(t, err)
+	defer os.Remove(zipFile.Name())
+	writer := zip.NewWriter(zipFile)
+	for _, id := range []string{"GO-2022-0001", "GO-2022-0002"} {
+		content, err := ioutil.ReadFile(filepath.Join(osvTestDir, "ID", id+".json"))
+		assert.NoError(t, err)
+		fileWriter, err := writer.Create(id + ".json")
+		assert.NoError(t, err)
+		_, err = fileWriter.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, zipFile.Close())
+	db, err = LoadOsvDatabase(zipFile.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, db.Size())
+
+	_, err = LoadOsvDatabase(filepath.Join(osvTestDir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestOsvDatabaseMatch(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	db, err := LoadOsvDatabase(osvTestDir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	tests := []struct {
+		module   string
+		version  string
+		expected []string
+	}{
+		{"github.com/jfrog/lib", "v0.0.0-20200101000000-abcdefabcdef", []string{"GO-2022-0001"}},
+		{"github.com/jfrog/lib", "v1.2.2", []string{"GO-2022-0001"}},
+		{"github.com/jfrog/lib", "v1.2.3", nil},
+		{"github.com/jfrog/lib", "v1.3.0", []string{"GO-2022-0001"}},
+		{"github.com/jfrog/lib", "v1.3.1", nil},
+		{"github.com/jfrog/lib/v2", "v2.0.0", nil},
+		{"github.com/jfrog/docker", "v1.13.1", []string{"GO-2022-0002"}},
+		{"github.com/jfrog/docker", "v16.0.0+incompatible", nil},
+		{"github.com/jfrog/docker", "v17.0.0+incompatible", []string{"GO-2022-0002"}},
+		{"github.com/jfrog/docker", "v20.10.11+incompatible", []string{"GO-2022-0002"}},
+		{"github.com/jfrog/docker", "v20.10.12+incompatible", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.module+"@"+test.version, func(t *testing.T) {
+			var ids []string
+			for _, entry := range db.Match(test.module, test.version) {
+				ids = append(ids, entry.Id)
+			}
+			assert.Equal(t, test.expected, ids)
+		})
+	}
+	entries := db.Match("github.com/jfrog/lib", "v1.0.0")
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "v1.2.3", entries[0].FixedVersion("github.com/jfrog/lib", "v1.0.0"))
+		assert.Equal(t, "v1.3.1", entries[0].FixedVersion("github.com/jfrog/lib", "v1.3.0"))
+	}
+
+	// Only the Go packages are matched, by both the database and the entry.
+	other := &OsvEntry{Id: "OTHER-1", Affected: []OsvAffected{{Package: OsvPackage{Name: "github.com/jfrog/lib"}, Versions: []string{"1.0.0"}}}}
+	assert.False(t, other.Affects("github.com/jfrog/lib", "v1.0.0"))
+	other.Affected[0].Package.Ecosystem = "Go"
+	assert.True(t, other.Affects("github.com/jfrog/lib", "v1.0.0"))
+}
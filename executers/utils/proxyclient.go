@@ -0,0 +1,299 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/mod/module"
+)
+
+var (
+	// The http client shared by all the proxy clients, so connections are pooled between them.
+	sharedHttpClient     *httpclient.HttpClient
+	sharedHttpClientErr  error
+	sharedHttpClientOnce sync.Once
+)
+
+func getSharedHttpClient() (*httpclient.HttpClient, error) {
+	sharedHttpClientOnce.Do(func() {
+		sharedHttpClient, sharedHttpClientErr = httpclient.ClientBuilder().Build()
+	})
+	return sharedHttpClient, sharedHttpClientErr
+}
+
+// Returned when the requested module or version does not exist in the repository (404).
+type ModuleNotFoundError struct {
+	Module  string
+	Version string
+	Url     string
+}
+
+func (e *ModuleNotFoundError) Error() string {
+	return fmt.Sprintf("%s was not found in Artifactory: %s", moduleVersionString(e.Module, e.Version), e.Url)
+}
+
+// Returned when the requested module or version was removed from the repository, or is not allowed to be served (410).
+type ModuleGoneError struct {
+	Module  string
+	Version string
+	Url     string
+}
+
+func (e *ModuleGoneError) Error() string {
+	return fmt.Sprintf("%s is no longer available in Artifactory: %s", moduleVersionString(e.Module, e.Version), e.Url)
+}
+
+func moduleVersionString(modulePath, version string) string {
+	if version == "" {
+		return modulePath
+	}
+	return modulePath + "@" + version
+}
+
+// The content of the <version>.info and @latest responses.
+type ModuleInfo struct {
+	Version string        `json:"Version,omitempty"`
+	Time    time.Time     `json:"Time,omitempty"`
+	Origin  *ModuleOrigin `json:"Origin,omitempty"`
+}
+
+// The origin of a module version, as reported by the go command since go1.19.
+type ModuleOrigin struct {
+	VCS    string `json:"VCS,omitempty"`
+	URL    string `json:"URL,omitempty"`
+	Subdir string `json:"Subdir,omitempty"`
+	Hash   string `json:"Hash,omitempty"`
+	Ref    string `json:"Ref,omitempty"`
+}
+
+// ProxyClient implements the GOPROXY protocol against a Go repository in Artifactory.
+// Module paths and versions are passed unescaped, and escaped by the client.
+type ProxyClient struct {
+	details     auth.ServiceDetails
+	repo        string
+	client      *httpclient.HttpClient
+	httpDetails httputils.HttpClientDetails
+	logger      log.Log
+	observer    Observer
+}
+
+// Creates a client for the provided Go repository, which uses the http client shared by all the proxy clients.
+func NewProxyClient(details auth.ServiceDetails, repo string) (*ProxyClient, error) {
+	client, err := getSharedHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	return NewProxyClientWithHttpClient(details, repo, client), nil
+}
+
+// Creates a client for the provided Go repository, which uses the provided http client.
+func NewProxyClientWithHttpClient(details auth.ServiceDetails, repo string, client *httpclient.HttpClient) *ProxyClient {
+	return &ProxyClient{details: details, repo: repo, client: client, httpDetails: details.CreateHttpClientDetails()}
+}
+
+// Sets the logger used by the client, instead of the global logger. The repository is added to the fields of the logger.
+func (pc *ProxyClient) SetLogger(logger log.Log) {
+	pc.logger = WithLogFields(logger, LogFields{"repo": pc.repo})
+}
+
+// Sets the observer which receives the download events of the module zips. If not set, no events are sent.
+func (pc *ProxyClient) SetObserver(observer Observer) {
+	pc.observer = observer
+}
+
+func (pc *ProxyClient) getLogger() log.Log {
+	if pc.logger != nil {
+		return pc.logger
+	}
+	return GetGlobalLogger()
+}
+
+func (pc *ProxyClient) GetRepo() string {
+	return pc.repo
+}
+
+func (pc *ProxyClient) GetServiceDetails() auth.ServiceDetails {
+	return pc.details
+}
+
+// Returns the GOPROXY URL of the repository, without credentials.
+func (pc *ProxyClient) GetBaseUrl() string {
+	return clientutils.AddTrailingSlashIfNeeded(pc.details.GetUrl()) + "api/go/" + pc.repo
+}
+
+// Returns the known versions of the module (the @v/list endpoint).
+func (pc *ProxyClient) List(modulePath string) ([]string, error) {
+	body, err := pc.get(modulePath, "", "/@v/list")
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, line := range strings.Split(string(body), "\n") {
+		// Each line may include additional fields after the version.
+		if fields := strings.Fields(line); len(fields) > 0 {
+			versions = append(versions, fields[0])
+		}
+	}
+	return versions, nil
+}
+
+// Returns the details of the module version (the @v/<version>.info endpoint).
+// The version may also be a query, such as a branch name, which is resolved to a canonical version.
+func (pc *ProxyClient) Info(modulePath, version string) (*ModuleInfo, error) {
+	body, err := pc.InfoContent(modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	return parseModuleInfo(body)
+}
+
+// Returns the .info file of the module version as is, for example to copy it without losing fields which ModuleInfo doesn't include.
+func (pc *ProxyClient) InfoContent(modulePath, version string) ([]byte, error) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return pc.get(modulePath, version, "/@v/"+escapedVersion+".info")
+}
+
+// Returns the go.mod file of the module version (the @v/<version>.mod endpoint).
+func (pc *ProxyClient) Mod(modulePath, version string) ([]byte, error) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return pc.get(modulePath, version, "/@v/"+escapedVersion+".mod")
+}
+
+// Streams the zip of the module version (the @v/<version>.zip endpoint) into the writer.
+// Returns the number of bytes written. If an observer is set, the download events are sent to it, including BytesTransferred as the zip is read.
+func (pc *ProxyClient) Zip(modulePath, version string, writer io.Writer) (written int64, err error) {
+	if pc.observer == nil {
+		return pc.zip(modulePath, version, writer, nil)
+	}
+	event := ProgressEvent{Operation: DownloadOperation, ModuleId: modulePath + ":" + version, Repo: pc.repo}
+	pc.observer.ModuleStarted(event)
+	written, err = pc.zip(modulePath, version, writer, &event)
+	event.Bytes = written
+	NotifyFinished(pc.observer, event, err)
+	return
+}
+
+func (pc *ProxyClient) zip(modulePath, version string, writer io.Writer, event *ProgressEvent) (int64, error) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return 0, errorutils.CheckError(err)
+	}
+	url, err := pc.moduleUrl(modulePath, "/@v/"+escapedVersion+".zip")
+	if err != nil {
+		return 0, err
+	}
+	pc.getLogger().Debug("Downloading module zip from Artifactory:", url)
+	reader, resp, err := pc.client.ReadRemoteFile(url, pc.httpDetails)
+	if err != nil {
+		return 0, errorutils.CheckError(err)
+	}
+	if reader == nil {
+		if resp.Body != nil {
+			resp.Body.Close()
+		}
+		return 0, statusError(resp, modulePath, version, url)
+	}
+	defer reader.Close()
+	var source io.Reader = reader
+	if event != nil {
+		source = NewProgressReader(reader, pc.observer, *event)
+	}
+	written, err := io.Copy(writer, source)
+	return written, errorutils.CheckError(err)
+}
+
+// Returns the details of the latest version of the module (the @latest endpoint).
+func (pc *ProxyClient) Latest(modulePath string) (*ModuleInfo, error) {
+	body, err := pc.get(modulePath, "", "/@latest")
+	if err != nil {
+		return nil, err
+	}
+	return parseModuleInfo(body)
+}
+
+// Returns true if the go.mod file of the module version exists in the repository.
+func (pc *ProxyClient) Exists(modulePath, version string) (bool, error) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	url, err := pc.moduleUrl(modulePath, "/@v/"+escapedVersion+".mod")
+	if err != nil {
+		return false, err
+	}
+	resp, _, err := pc.client.SendHead(url, pc.httpDetails, "")
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	pc.getLogger().Debug("Artifactory head request response for", url, ":", resp.StatusCode)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound, http.StatusGone:
+		return false, nil
+	}
+	return false, statusError(resp, modulePath, version, url)
+}
+
+// Sends a GET request to the endpoint of the module, and returns the response body.
+func (pc *ProxyClient) get(modulePath, version, endpoint string) ([]byte, error) {
+	url, err := pc.moduleUrl(modulePath, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return pc.getUrl(url, modulePath, version)
+}
+
+func (pc *ProxyClient) getUrl(url, modulePath, version string) ([]byte, error) {
+	pc.getLogger().Debug("Sending GET request to Artifactory:", url)
+	resp, body, _, err := pc.client.SendGet(url, true, pc.httpDetails, "")
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp, modulePath, version, url)
+	}
+	return body, nil
+}
+
+func (pc *ProxyClient) moduleUrl(modulePath, endpoint string) (string, error) {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return pc.GetBaseUrl() + "/" + escapedPath + endpoint, nil
+}
+
+func statusError(resp *http.Response, modulePath, version, url string) error {
+	if err := NewStatusError(resp.StatusCode, modulePath, version, url); err != nil {
+		return err
+	}
+	return errorutils.CheckError(errors.New("Artifactory response: " + resp.Status))
+}
+
+func parseModuleInfo(body []byte) (*ModuleInfo, error) {
+	info := &ModuleInfo{}
+	err := json.Unmarshal(body, info)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return info, nil
+}
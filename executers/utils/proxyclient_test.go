@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	artifactoryauth "github.com/jfrog/jfrog-client-go/artifactory/auth"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+const proxyClientTestRepo = "go-remote"
+
+func createProxyTestServer(t *testing.T) (*httptest.Server, auth.ServiceDetails) {
+	responses := map[string]string{
+		"/api/go/go-remote/github.com/!burnt!sushi/toml/@v/list":        "v0.3.0\nv0.3.1 2019-01-01T00:00:00Z\n\n",
+		"/api/go/go-remote/github.com/!burnt!sushi/toml/@v/v0.3.1.info": `{"Version":"v0.3.1","Time":"2018-08-14T18:45:08Z","Origin":{"VCS":"git","URL":"https://github.com/BurntSushi/toml","Hash":"b26d9c308763d68093482582cea63d69be07a0f0","Ref":"refs/tags/v0.3.1"}}`,
+		"/api/go/go-remote/github.com/!burnt!sushi/toml/@v/v0.3.1.mod":  "module github.com/BurntSushi/toml\n",
+		"/api/go/go-remote/github.com/!burnt!sushi/toml/@v/v0.3.1.zip":  "zip content",
+		"/api/go/go-remote/github.com/!burnt!sushi/toml/@latest":        `{"Version":"v0.3.1","Time":"2018-08-14T18:45:08Z"}`,
+		"/api/go/go-remote/github.com/!burnt!sushi/toml/@v/v!r!c1.info": `{"Version":"v0.4.0-RC1"}`,
+		"/api/go/go-remote/github.com/jfrog/jfrog-cli/@v/master.info":   `{"Version":"v1.2.3"}`,
+	}
+	gone := map[string]bool{"/api/go/go-remote/github.com/gone/module/@v/v1.0.0.info": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "user" || password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if gone[r.URL.EscapedPath()] {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		response, ok := responses[r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	details := artifactoryauth.NewArtifactoryDetails()
+	details.SetUrl(server.URL + "/")
+	details.SetUser("user")
+	details.SetPassword("password")
+	return server, details
+}
+
+func TestProxyClient(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	server, details := createProxyTestServer(t)
+	defer server.Close()
+	proxyClient, err := NewProxyClient(details, proxyClientTestRepo)
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/api/go/go-remote", proxyClient.GetBaseUrl())
+
+	versions, err := proxyClient.List("github.com/BurntSushi/toml")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v0.3.0", "v0.3.1"}, versions)
+
+	info, err := proxyClient.Info("github.com/BurntSushi/toml", "v0.3.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.3.1", info.Version)
+	assert.Equal(t, time.Date(2018, 8, 14, 18, 45, 8, 0, time.UTC), info.Time)
+	if assert.NotNil(t, info.Origin) {
+		assert.Equal(t, "git", info.Origin.VCS)
+		assert.Equal(t, "refs/tags/v0.3.1", info.Origin.Ref)
+	}
+
+	infoContent, err := proxyClient.InfoContent("github.com/BurntSushi/toml", "v0.3.1")
+	assert.NoError(t, err)
+	assert.Contains(t, string(infoContent), `"Ref":"refs/tags/v0.3.1"`)
+
+	// Upper case letters in versions are escaped too.
+	info, err = proxyClient.Info("github.com/BurntSushi/toml", "vRC1")
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.4.0-RC1", info.Version)
+
+	mod, err := proxyClient.Mod("github.com/BurntSushi/toml", "v0.3.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "module github.com/BurntSushi/toml\n", string(mod))
+
+	zipContent := &bytes.Buffer{}
+	written, err := proxyClient.Zip("github.com/BurntSushi/toml", "v0.3.1", zipContent)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len("zip content")), written)
+	assert.Equal(t, "zip content", zipContent.String())
+
+	// The bytes of the zip are reported as they are read.
+	observer := &bytesObserver{}
+	proxyClient.SetObserver(observer)
+	_, err = proxyClient.Zip("github.com/BurntSushi/toml", "v0.3.1", &bytes.Buffer{})
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, observer.bytes) {
+		assert.Equal(t, int64(len("zip content")), observer.bytes[len(observer.bytes)-1])
+	}
+	proxyClient.SetObserver(nil)
+
+	latest, err := proxyClient.Latest("github.com/BurntSushi/toml")
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.3.1", latest.Version)
+
+	exists, err := proxyClient.Exists("github.com/BurntSushi/toml", "v0.3.1")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = proxyClient.Exists("github.com/BurntSushi/toml", "v9.9.9")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestProxyClientErrors(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	server, details := createProxyTestServer(t)
+	defer server.Close()
+	proxyClient, err := NewProxyClient(details, proxyClientTestRepo)
+	assert.NoError(t, err)
+
+	_, err = proxyClient.Mod("github.com/BurntSushi/toml", "v9.9.9")
+	var notFoundErr *ModuleNotFoundError
+	if assert.True(t, errors.As(err, &notFoundErr)) {
+		assert.Equal(t, "github.com/BurntSushi/toml", notFoundErr.Module)
+		assert.Equal(t, "v9.9.9", notFoundErr.Version)
+	}
+
+	_, err = proxyClient.Zip("github.com/BurntSushi/toml", "v9.9.9", &bytes.Buffer{})
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	_, err = proxyClient.Info("github.com/gone/module", "v1.0.0")
+	var goneErr *ModuleGoneError
+	assert.True(t, errors.As(err, &goneErr))
+
+	// Invalid module paths are rejected before sending a request.
+	_, err = proxyClient.List("github.com/invalid path")
+	assert.Error(t, err)
+
+	// Other failures are not typed.
+	details.SetPassword("wrong")
+	_, err = NewProxyClientWithHttpClient(details, proxyClientTestRepo, proxyClient.client).Latest("github.com/BurntSushi/toml")
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &notFoundErr))
+}
+
+func TestGetPackageVersion(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	server, details := createProxyTestServer(t)
+	defer server.Close()
+	version, err := GetPackageVersion(proxyClientTestRepo, "github.com/jfrog/jfrog-cli/@v/master.info", details)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.3", version)
+}
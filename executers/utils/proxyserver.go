@@ -0,0 +1,276 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ProxyHandler serves the GOPROXY protocol from a directory laid out like the 'cache/download' directory of the Go module cache:
+// <root>/<escaped module path>/@v/<escaped version>.{info,mod,zip}
+// Missing .info and .mod files are derived from the existing files, so a directory which includes only zips can be served too.
+type ProxyHandler struct {
+	root   string
+	logger log.Log
+}
+
+// Creates a handler which serves the modules found in the root directory.
+// To serve the Go module cache, use the path returned by cmd.GetCachePath() as the root.
+func NewProxyHandler(root string) *ProxyHandler {
+	return &ProxyHandler{root: root}
+}
+
+// Sets the logger used by the handler, instead of the global logger.
+func (handler *ProxyHandler) SetLogger(logger log.Log) {
+	handler.logger = logger
+}
+
+func (handler *ProxyHandler) getLogger() log.Log {
+	if handler.logger != nil {
+		return handler.logger
+	}
+	return GetGlobalLogger()
+}
+
+func (handler *ProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	escapedPath, endpoint, ok := splitProxyRequestPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	modulePath, err := module.UnescapePath(escapedPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	handler.getLogger().Debug("Serving GOPROXY request:", r.URL.Path)
+	moduleDir := filepath.Join(handler.root, filepath.FromSlash(escapedPath), "@v")
+	switch {
+	case endpoint == "@latest":
+		handler.serveLatest(w, r, moduleDir)
+	case endpoint == "@v/list":
+		handler.serveList(w, moduleDir)
+	default:
+		handler.serveVersionFile(w, r, moduleDir, modulePath, strings.TrimPrefix(endpoint, "@v/"))
+	}
+}
+
+// Splits the request path to the escaped module path and the endpoint, which starts with '@v/' or equals '@latest'.
+func splitProxyRequestPath(requestPath string) (escapedPath, endpoint string, ok bool) {
+	requestPath = strings.TrimPrefix(requestPath, "/")
+	if strings.HasSuffix(requestPath, "/@latest") {
+		return strings.TrimSuffix(requestPath, "/@latest"), "@latest", true
+	}
+	index := strings.LastIndex(requestPath, "/@v/")
+	if index <= 0 {
+		return "", "", false
+	}
+	return requestPath[:index], requestPath[index+1:], true
+}
+
+func (handler *ProxyHandler) serveList(w http.ResponseWriter, moduleDir string) {
+	versions, err := listCachedVersions(moduleDir)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	for _, version := range versions {
+		// Pseudo-versions are not listed, as defined by the GOPROXY protocol.
+		if !module.IsPseudoVersion(version) {
+			fmt.Fprintln(w, version)
+		}
+	}
+}
+
+func (handler *ProxyHandler) serveLatest(w http.ResponseWriter, r *http.Request, moduleDir string) {
+	versions, err := listCachedVersions(moduleDir)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	latest := latestVersion(versions)
+	if latest == "" {
+		http.NotFound(w, r)
+		return
+	}
+	escapedVersion, err := module.EscapeVersion(latest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	handler.serveInfo(w, r, moduleDir, escapedVersion, latest)
+}
+
+func (handler *ProxyHandler) serveVersionFile(w http.ResponseWriter, r *http.Request, moduleDir, modulePath, fileName string) {
+	ext := path.Ext(fileName)
+	escapedVersion := strings.TrimSuffix(fileName, ext)
+	version, err := module.UnescapeVersion(escapedVersion)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch ext {
+	case ".info":
+		handler.serveInfo(w, r, moduleDir, escapedVersion, version)
+	case ".mod":
+		handler.serveMod(w, r, moduleDir, modulePath, escapedVersion, version)
+	case ".zip":
+		handler.serveFile(w, r, filepath.Join(moduleDir, escapedVersion+".zip"), "application/zip")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (handler *ProxyHandler) serveInfo(w http.ResponseWriter, r *http.Request, moduleDir, escapedVersion, version string) {
+	infoPath := filepath.Join(moduleDir, escapedVersion+".info")
+	if fileutils.IsPathExists(infoPath, false) {
+		handler.serveFile(w, r, infoPath, "application/json")
+		return
+	}
+	// Derive the info from the modification time of the zip or the mod file.
+	for _, ext := range []string{".zip", ".mod"} {
+		stat, err := os.Stat(filepath.Join(moduleDir, escapedVersion+ext))
+		if err != nil {
+			continue
+		}
+		content, err := json.Marshal(ModuleInfo{Version: version, Time: stat.ModTime().UTC()})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeContent(w, r, content, "application/json")
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (handler *ProxyHandler) serveMod(w http.ResponseWriter, r *http.Request, moduleDir, modulePath, escapedVersion, version string) {
+	modPath := filepath.Join(moduleDir, escapedVersion+".mod")
+	if fileutils.IsPathExists(modPath, false) {
+		handler.serveFile(w, r, modPath, "text/plain; charset=UTF-8")
+		return
+	}
+	zipPath := filepath.Join(moduleDir, escapedVersion+".zip")
+	if !fileutils.IsPathExists(zipPath, false) {
+		http.NotFound(w, r)
+		return
+	}
+	content, err := readModFromZip(zipPath, modulePath, version)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeContent(w, r, content, "text/plain; charset=UTF-8")
+}
+
+func (handler *ProxyHandler) serveFile(w http.ResponseWriter, r *http.Request, filePath, contentType string) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	http.ServeContent(w, r, filepath.Base(filePath), stat.ModTime(), file)
+}
+
+func writeContent(w http.ResponseWriter, r *http.Request, content []byte, contentType string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+	if r.Method != http.MethodHead {
+		w.Write(content)
+	}
+}
+
+// Returns the versions which have at least one file in the module directory, sorted by semver.
+func listCachedVersions(moduleDir string) ([]string, error) {
+	files, err := ioutil.ReadDir(moduleDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	versionsSet := map[string]bool{}
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || (ext != ".info" && ext != ".mod" && ext != ".zip") {
+			continue
+		}
+		version, err := module.UnescapeVersion(strings.TrimSuffix(file.Name(), ext))
+		if err != nil || !semver.IsValid(version) {
+			continue
+		}
+		versionsSet[version] = true
+	}
+	var versions []string
+	for version := range versionsSet {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare(versions[i], versions[j]) < 0
+	})
+	return versions, nil
+}
+
+// Returns the latest release version. If there are no releases, the latest pre-release or pseudo-version is returned.
+func latestVersion(sortedVersions []string) string {
+	for i := len(sortedVersions) - 1; i >= 0; i-- {
+		if semver.Prerelease(sortedVersions[i]) == "" {
+			return sortedVersions[i]
+		}
+	}
+	if len(sortedVersions) > 0 {
+		return sortedVersions[len(sortedVersions)-1]
+	}
+	return ""
+}
+
+// Returns the go.mod file from the module zip. If the module has no go.mod file, a minimal one is returned, as the go command does.
+func readModFromZip(zipPath, modulePath, version string) ([]byte, error) {
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	modFileName := modulePath + "@" + version + "/go.mod"
+	for _, file := range reader.File {
+		if file.Name != modFileName {
+			continue
+		}
+		fileReader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer fileReader.Close()
+		return ioutil.ReadAll(fileReader)
+	}
+	return []byte(fmt.Sprintf("module %s\n", modulePath)), nil
+}
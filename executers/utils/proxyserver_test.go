@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	artifactoryauth "github.com/jfrog/jfrog-client-go/artifactory/auth"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// Serves the provided directory at the same path as an Artifactory Go repository, so it can be accessed by a ProxyClient.
+func createProxyHandlerTestClient(t *testing.T, root string) (*httptest.Server, *ProxyClient) {
+	mux := http.NewServeMux()
+	mux.Handle("/api/go/go-local/", http.StripPrefix("/api/go/go-local", NewProxyHandler(root)))
+	server := httptest.NewServer(mux)
+	details := artifactoryauth.NewArtifactoryDetails()
+	details.SetUrl(server.URL + "/")
+	proxyClient, err := NewProxyClient(details, "go-local")
+	assert.NoError(t, err)
+	return server, proxyClient
+}
+
+func TestProxyHandlerZipsOnly(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	server, proxyClient := createProxyHandlerTestClient(t, filepath.Join("..", "..", "testdata", "zip"))
+	defer server.Close()
+
+	versions, err := proxyClient.List("rsc.io/quote")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1.5.2"}, versions)
+
+	latest, err := proxyClient.Latest("rsc.io/quote")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.5.2", latest.Version)
+	assert.False(t, latest.Time.IsZero())
+
+	// The zip doesn't include a go.mod file, so a minimal one is served.
+	mod, err := proxyClient.Mod("rsc.io/quote", "v1.5.2")
+	assert.NoError(t, err)
+	assert.Equal(t, "module rsc.io/quote\n", string(mod))
+
+	expectedZip, err := ioutil.ReadFile(filepath.Join("..", "..", "testdata", "zip", "rsc.io", "quote", "@v", "v1.5.2.zip"))
+	assert.NoError(t, err)
+	zipContent := &bytes.Buffer{}
+	_, err = proxyClient.Zip("rsc.io/quote", "v1.5.2", zipContent)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedZip, zipContent.Bytes())
+
+	var notFoundErr *ModuleNotFoundError
+	_, err = proxyClient.Info("rsc.io/quote", "v1.5.3")
+	assert.True(t, errors.As(err, &notFoundErr))
+	_, err = proxyClient.Latest("rsc.io/sampler")
+	assert.True(t, errors.As(err, &notFoundErr))
+	versions, err = proxyClient.List("rsc.io/sampler")
+	assert.NoError(t, err)
+	assert.Empty(t, versions)
+}
+
+func TestProxyHandlerCacheLayout(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	root, err := ioutil.TempDir("", "proxyHandler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	moduleDir := filepath.Join(root, "github.com", "!burnt!sushi", "toml", "@v")
+	assert.NoError(t, os.MkdirAll(moduleDir, 0755))
+	files := map[string]string{
+		"v0.3.0.info":    `{"Version":"v0.3.0","Time":"2017-03-28T06:15:53Z"}`,
+		"v0.3.0.mod":     "module github.com/BurntSushi/toml\n",
+		"v0.4.0-rc1.mod": "module github.com/BurntSushi/toml\n",
+		"v0.0.0-20200101000000-abcdefabcdef.info": `{"Version":"v0.0.0-20200101000000-abcdefabcdef"}`,
+		"list":        "v0.3.0\n",
+		"v0.3.0.lock": "",
+	}
+	for name, content := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(moduleDir, name), []byte(content), 0644))
+	}
+	server, proxyClient := createProxyHandlerTestClient(t, root)
+	defer server.Close()
+
+	// Pseudo-versions are not listed.
+	versions, err := proxyClient.List("github.com/BurntSushi/toml")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v0.3.0", "v0.4.0-rc1"}, versions)
+
+	// Releases are preferred over pre-releases.
+	latest, err := proxyClient.Latest("github.com/BurntSushi/toml")
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.3.0", latest.Version)
+	assert.Equal(t, "2017-03-28T06:15:53Z", latest.Time.Format("2006-01-02T15:04:05Z07:00"))
+
+	mod, err := proxyClient.Mod("github.com/BurntSushi/toml", "v0.4.0-rc1")
+	assert.NoError(t, err)
+	assert.Equal(t, "module github.com/BurntSushi/toml\n", string(mod))
+
+	resp, err := http.Post(proxyClient.GetBaseUrl()+"/github.com/!burnt!sushi/toml/@v/list", "text/plain", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	resp.Body.Close()
+}
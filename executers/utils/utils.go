@@ -2,16 +2,13 @@ package utils
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
-	"net/http"
 	"net/url"
 	"os"
 	"regexp"
 
 	"github.com/jfrog/gocmd/cache"
 	"github.com/jfrog/jfrog-client-go/auth"
-	"github.com/jfrog/jfrog-client-go/http/httpclient"
 	"github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
@@ -63,23 +60,14 @@ func GetArtifactoryApiUrl(repoName string, details auth.ServiceDetails) (string,
 // PackageName string should be in the following format: <Package Path>/@V/<Requested Branch Name>.info OR latest.info
 // For example the jfrog/jfrog-cli/@v/master.info packageName will return the corresponding canonical version (vX.Y.Z) string for the jfrog-cli master branch.
 func GetPackageVersion(repoName, packageName string, details auth.ServiceDetails) (string, error) {
-	artifactoryApiUrl, err := GetArtifactoryApiUrl(repoName, details)
+	proxyClient, err := NewProxyClient(details, repoName)
 	if err != nil {
 		return "", err
 	}
-	artHttpDetails := details.CreateHttpClientDetails()
-	client, err := httpclient.ClientBuilder().Build()
+	body, err := proxyClient.getUrl(proxyClient.GetBaseUrl()+"/"+packageName, packageName, "")
 	if err != nil {
 		return "", err
 	}
-	artifactoryApiUrl = artifactoryApiUrl + "/" + packageName
-	resp, body, _, err := client.SendGet(artifactoryApiUrl, true, artHttpDetails, "")
-	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", errorutils.CheckError(errors.New("Artifactory response: " + resp.Status))
-	}
 	// Extract version from response
 	var version PackageVersionResponseContent
 	err = json.Unmarshal(body, &version)
@@ -89,6 +77,7 @@ func GetPackageVersion(repoName, packageName string, details auth.ServiceDetails
 	return version.Version, nil
 }
 
+// Deprecated: The regular expressions do not support all the go.mod syntax. Use ParseGoMod or ReadGoMod instead.
 func GetRegex() (regExp *RegExp, err error) {
 	emptyRegex, err := utils.GetRegExp(`^\s*require (?:[\(\w\.@:%_\+-.~#?&]?.+)`)
 	if err != nil {
@@ -0,0 +1,335 @@
+package executers
+
+import (
+	"archive/zip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Go module zips use a fixed modification time, so the reconstructed zips are reproducible.
+var vendoredZipModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// GetVendoredDependencies creates a dependency for every module listed in the vendor/modules.txt file of the project.
+// Since vendored modules have no zip in the Go cache, the build-info checksums are calculated from the vendored sources.
+// If zipsDir is not empty, a module zip is reconstructed for each module in zipsDir, using the Go cache layout, so that it can be published.
+// Since 'go mod vendor' copies only the needed packages, a reconstructed zip usually differs from the original module zip.
+// Therefore, a reconstructed zip is kept only if its h1 hash matches the go.sum file of the project.
+// Otherwise, the dependency has no zip, and publishing it fails.
+// Modules replaced by a local directory are skipped, since they cannot be published.
+func GetVendoredDependencies(projectDir, cachePath, zipsDir string, options ...utils.Option) ([]Package, error) {
+	_, deps, err := getVendoredDependencies(projectDir, cachePath, zipsDir, utils.NewOptions(options...))
+	return deps, err
+}
+
+// Returns the vendored modules which aren't replaced by a local directory, and a dependency for each of them, in the same order.
+func getVendoredDependencies(projectDir, cachePath, zipsDir string, opts *utils.Options) ([]cmd.VendoredModule, []Package, error) {
+	modules, err := cmd.GetVendoredModules(projectDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if projectDir == "" {
+		projectDir, err = cmd.GetProjectRoot()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	goSum := map[string]utils.GoSumEntry{}
+	if zipsDir != "" {
+		goSum, err = readProjectGoSum(projectDir)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	vendorDir := cmd.GetVendorDir(projectDir)
+	var vendored []cmd.VendoredModule
+	var deps []Package
+	for i := range modules {
+		if modules[i].Replace != nil && modules[i].Replace.IsLocal() {
+			opts.GetLogger().Debug(fmt.Sprintf("Skipping %s, since it is replaced by the local directory %s", modules[i].Path, modules[i].Replace.Path))
+			continue
+		}
+		dep, err := createVendoredDependency(vendorDir, cachePath, zipsDir, &modules[i], goSum, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		vendored = append(vendored, modules[i])
+		deps = append(deps, *dep)
+	}
+	return vendored, deps, nil
+}
+
+// GetProjectDependencies runs GetDependencies, and if the project at projectDir is built in vendor mode, merges the vendored modules into the result.
+// The dependencies are merged by the module path and version listed in the vendor/modules.txt file:
+// A module which is replaced by another module version is taken from the vendored sources of the replacement, since the cached zip of the original module isn't used by the build.
+// Other vendored modules are added only if they have no zip in the Go cache.
+// The build-info checksums of the added modules are calculated from the vendored sources. See GetVendoredDependencies.
+func GetProjectDependencies(projectDir, cachePath string, dependenciesList map[string]bool, options ...utils.Option) ([]Package, error) {
+	deps, err := GetDependencies(cachePath, dependenciesList, options...)
+	if err != nil {
+		return nil, err
+	}
+	vendorMode, err := cmd.IsVendorMode(projectDir)
+	if err != nil || !vendorMode {
+		return deps, err
+	}
+	modules, vendoredDeps, err := getVendoredDependencies(projectDir, cachePath, "", utils.NewOptions(options...))
+	if err != nil {
+		return nil, err
+	}
+	replaced := map[string]bool{}
+	for i := range modules {
+		if modules[i].Replace != nil {
+			replaced[getVendoredModuleId(&modules[i])] = true
+		}
+	}
+	var merged []Package
+	inCache := map[string]bool{}
+	for i := range deps {
+		if !replaced[deps[i].GetId()] {
+			merged = append(merged, deps[i])
+			inCache[deps[i].GetId()] = true
+		}
+	}
+	for i := range vendoredDeps {
+		if !inCache[getVendoredModuleId(&modules[i])] {
+			merged = append(merged, vendoredDeps[i])
+		}
+	}
+	return merged, nil
+}
+
+// Returns the id of the package of the module listed in vendor/modules.txt, before its replacement.
+func getVendoredModuleId(module *cmd.VendoredModule) string {
+	return goModEncode(module.Path) + ":" + goModEncode(module.Version)
+}
+
+// Returns the entries of the go.sum file of the project by <module path>@<version>. Returns an empty map if the project has no go.sum file.
+func readProjectGoSum(projectDir string) (map[string]utils.GoSumEntry, error) {
+	goSumPath := filepath.Join(projectDir, "go.sum")
+	entries := map[string]utils.GoSumEntry{}
+	exists, err := fileutils.IsFileExists(goSumPath, false)
+	if err != nil || !exists {
+		return entries, err
+	}
+	goSum, err := utils.ReadGoSum(goSumPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range goSum {
+		entries[entry.Path+"@"+entry.Version] = entry
+	}
+	return entries, nil
+}
+
+func createVendoredDependency(vendorDir, cachePath, zipsDir string, module *cmd.VendoredModule, goSum map[string]utils.GoSumEntry, opts *utils.Options) (*Package, error) {
+	logger := opts.GetLogger()
+	sourcePath, sourceVersion := module.Source()
+	name := goModEncode(sourcePath)
+	version := goModEncode(sourceVersion)
+	files, err := listVendoredModuleFiles(vendorDir, module)
+	if err != nil {
+		return nil, err
+	}
+
+	dep := Package{}
+	dep.setOptions(opts)
+	dep.id = strings.Join([]string{name, version}, ":")
+	dep.version = version
+	checksum, err := calcVendoredChecksum(vendorDir, module.Path, files)
+	if err != nil {
+		return nil, err
+	}
+	dep.buildInfoDependencies = append(dep.buildInfoDependencies, buildinfo.Dependency{Id: dep.id, Type: "vendor", Checksum: checksum})
+
+	dep.modContent, err = getVendoredModContent(cachePath, name, version, sourcePath, logger)
+	if err != nil {
+		return nil, err
+	}
+	if zipsDir == "" {
+		return &dep, nil
+	}
+
+	moduleDir := filepath.Join(zipsDir, name, "@v")
+	err = fileutils.CreateDirIfNotExist(moduleDir)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	zipPath := filepath.Join(moduleDir, version+".zip")
+	err = createVendoredZip(vendorDir, module.Path, sourcePath+"@"+sourceVersion, files, zipPath, logger)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := verifyVendoredZip(zipPath, sourcePath, sourceVersion, goSum, logger)
+	if err != nil || !verified {
+		return &dep, err
+	}
+	dep.zipPath = zipPath
+	dep.modPath = filepath.Join(moduleDir, version+".mod")
+	err = ioutil.WriteFile(dep.modPath, dep.modContent, 0644)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return &dep, nil
+}
+
+// Returns true if the h1 hash of the reconstructed zip matches the go.sum entry of the module.
+// Otherwise, the zip is removed, since publishing it would break the downloads of the module version.
+func verifyVendoredZip(zipPath, modulePath, version string, goSum map[string]utils.GoSumEntry, logger log.Log) (bool, error) {
+	zipHash, err := utils.HashZip(zipPath)
+	if err != nil {
+		return false, err
+	}
+	expected := goSum[modulePath+"@"+version].ZipHash
+	if expected == zipHash {
+		return true, nil
+	}
+	if expected == "" {
+		logger.Warn(fmt.Sprintf("The reconstructed zip of %s@%s can't be verified, since it is missing from go.sum. It will not be published.", modulePath, version))
+	} else {
+		logger.Warn(fmt.Sprintf("The reconstructed zip of %s@%s doesn't match its go.sum hash, since not all its packages are vendored. It will not be published.", modulePath, version))
+	}
+	return false, errorutils.CheckError(os.Remove(zipPath))
+}
+
+// Returns the files of the vendored module, relative to the module directory inside the vendor directory, sorted.
+// The files of nested modules, which are vendored separately, are not included.
+func listVendoredModuleFiles(vendorDir string, module *cmd.VendoredModule) ([]string, error) {
+	moduleDir := filepath.Join(vendorDir, filepath.FromSlash(module.Path))
+	dirs := map[string]bool{moduleDir: true}
+	for _, pkg := range module.Packages {
+		dirs[filepath.Join(vendorDir, filepath.FromSlash(pkg))] = true
+	}
+	var files []string
+	for dir := range dirs {
+		exists, err := fileutils.IsDirExists(dir, false)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		for _, entry := range entries {
+			if !entry.Mode().IsRegular() {
+				continue
+			}
+			relPath, err := filepath.Rel(moduleDir, filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, errorutils.CheckError(err)
+			}
+			files = append(files, filepath.ToSlash(relPath))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Calculates the checksums of the vendored module.
+// The checksums are calculated over a summary of the module files, which includes the sha256 and the path of each file, similarly to the Go 'h1' hash.
+func calcVendoredChecksum(vendorDir, modulePath string, files []string) (*buildinfo.Checksum, error) {
+	sha1Hash := sha1.New()
+	md5Hash := md5.New()
+	summary := io.MultiWriter(sha1Hash, md5Hash)
+	for _, file := range files {
+		fileHash, err := calcFileSha256(filepath.Join(vendorDir, filepath.FromSlash(modulePath), filepath.FromSlash(file)))
+		if err != nil {
+			return nil, err
+		}
+		_, err = fmt.Fprintf(summary, "%s  %s\n", fileHash, file)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+	}
+	return &buildinfo.Checksum{Sha1: hex.EncodeToString(sha1Hash.Sum(nil)), Md5: hex.EncodeToString(md5Hash.Sum(nil))}, nil
+}
+
+func calcFileSha256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Returns the mod file content of the module from the Go cache.
+// Since go.mod files of dependencies are not vendored, a minimal mod file is returned if it is missing from the cache.
+func getVendoredModContent(cachePath, encodedName, encodedVersion, modulePath string, logger log.Log) ([]byte, error) {
+	if cachePath != "" {
+		modPath := filepath.Join(cachePath, encodedName, "@v", encodedVersion+".mod")
+		exists, err := fileutils.IsFileExists(modPath, false)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			content, err := ioutil.ReadFile(modPath)
+			return content, errorutils.CheckError(err)
+		}
+	}
+	logger.Debug(fmt.Sprintf("The mod file of %s@%s was not found in the Go cache. Using a minimal mod file.", modulePath, encodedVersion))
+	return []byte(fmt.Sprintf("module %s\n", modulePath)), nil
+}
+
+// Creates a module zip from the vendored files. The zip entries are prefixed with <module path>@<version>/, as expected by Go.
+func createVendoredZip(vendorDir, vendoredPath, zipPrefix string, files []string, zipPath string, logger log.Log) (err error) {
+	logger.Debug("Reconstructing module zip:", zipPath)
+	zipFile, err := os.Create(zipPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		e := zipFile.Close()
+		if err == nil {
+			err = errorutils.CheckError(e)
+		}
+	}()
+	zipWriter := zip.NewWriter(zipFile)
+	for _, file := range files {
+		err = addFileToZip(zipWriter, filepath.Join(vendorDir, filepath.FromSlash(vendoredPath), filepath.FromSlash(file)), zipPrefix+"/"+file)
+		if err != nil {
+			return err
+		}
+	}
+	return errorutils.CheckError(zipWriter.Close())
+}
+
+func addFileToZip(zipWriter *zip.Writer, sourcePath, entryName string) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer source.Close()
+	header := &zip.FileHeader{Name: entryName, Method: zip.Deflate}
+	header.Modified = vendoredZipModTime
+	writer, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = io.Copy(writer, source)
+	return errorutils.CheckError(err)
+}
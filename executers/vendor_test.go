@@ -0,0 +1,99 @@
+package executers
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVendoredDependencies(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	projectDir := filepath.Join("testdata", "vendorproject")
+	deps, err := GetVendoredDependencies(projectDir, "", "")
+	assert.NoError(t, err)
+	if assert.Len(t, deps, 2) {
+		assert.Equal(t, "github.com/jfrog/lib:v1.0.0", deps[0].GetId())
+		// The replacement is the source of the vendored files.
+		assert.Equal(t, "github.com/!forked/quote:v1.5.3", deps[1].GetId())
+		assert.Equal(t, "module github.com/Forked/quote\n", string(deps[1].GetModContent()))
+		for _, dep := range deps {
+			assert.Empty(t, dep.GetZipPath())
+			if assert.Len(t, dep.Dependencies(), 1) {
+				assert.NotEmpty(t, dep.Dependencies()[0].Sha1)
+				assert.NotEmpty(t, dep.Dependencies()[0].Md5)
+			}
+		}
+	}
+
+	// The checksums should be stable.
+	depsAgain, err := GetVendoredDependencies(projectDir, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, deps[0].Dependencies(), depsAgain[0].Dependencies())
+}
+
+func TestGetVendoredDependenciesWithZips(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	zipsDir, err := ioutil.TempDir("", "vendoredZips")
+	assert.NoError(t, err)
+	defer os.RemoveAll(zipsDir)
+
+	deps, err := GetVendoredDependencies(filepath.Join("testdata", "vendorproject"), "", zipsDir)
+	assert.NoError(t, err)
+	if !assert.Len(t, deps, 2) {
+		return
+	}
+	assert.Equal(t, filepath.Join(zipsDir, "github.com", "jfrog", "lib", "@v", "v1.0.0.zip"), deps[0].GetZipPath())
+	reader, err := zip.OpenReader(deps[0].GetZipPath())
+	assert.NoError(t, err)
+	defer reader.Close()
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	// Packages that are not listed in modules.txt are not part of the module.
+	assert.Equal(t, []string{"github.com/jfrog/lib@v1.0.0/LICENSE", "github.com/jfrog/lib@v1.0.0/sub/sub.go"}, names)
+
+	modContent, err := ioutil.ReadFile(filepath.Join(zipsDir, "github.com", "jfrog", "lib", "@v", "v1.0.0.mod"))
+	assert.NoError(t, err)
+	assert.Equal(t, deps[0].GetModContent(), modContent)
+	assert.NoError(t, deps[0].PopulateZip())
+
+	// The reconstructed zip of the replacement doesn't match go.sum, so it is removed and the dependency can't be published.
+	assert.Empty(t, deps[1].GetZipPath())
+	assert.NoFileExists(t, filepath.Join(zipsDir, "github.com", "!forked", "quote", "@v", "v1.5.3.zip"))
+	assert.Error(t, deps[1].Publish("", "go-local", nil))
+}
+
+func TestGetProjectDependencies(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	cachePath := createTestCache(t)
+	defer os.RemoveAll(cachePath)
+	projectDir := filepath.Join("testdata", "vendorproject")
+	dependencies := map[string]bool{"rsc.io/quote@v1.5.2": true, "github.com/jfrog/lib@v1.0.0": true}
+	defer os.Setenv("GOFLAGS", os.Getenv("GOFLAGS"))
+	assert.NoError(t, os.Setenv("GOFLAGS", ""))
+
+	// The vendored modules are added to the modules found in the cache, and the replaced module is taken from the vendored replacement.
+	deps, err := GetProjectDependencies(projectDir, cachePath, dependencies)
+	assert.NoError(t, err)
+	var ids []string
+	for _, dep := range deps {
+		ids = append(ids, dep.GetId())
+	}
+	assert.ElementsMatch(t, []string{"github.com/jfrog/lib:v1.0.0", "github.com/!forked/quote:v1.5.3"}, ids)
+
+	module, err := createBuildInfoModule("example.com/vendorproject", projectDir, dependencies, cachePath)
+	assert.NoError(t, err)
+	assert.Len(t, module.Dependencies, 2)
+
+	// The vendor directory is ignored if another -mod flag is set.
+	assert.NoError(t, os.Setenv("GOFLAGS", "-mod=mod"))
+	deps, err = GetProjectDependencies(projectDir, cachePath, dependencies)
+	assert.NoError(t, err)
+	assert.Len(t, deps, 1)
+}
@@ -0,0 +1,68 @@
+package executers
+
+import (
+	"sort"
+	"strings"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gocmd/executers/utils"
+	"golang.org/x/mod/semver"
+)
+
+// The prefix of the build-info module properties added by AddVulnerabilitiesProperties.
+const VulnerabilitiesPropertyPrefix = "go.vulnerabilities."
+
+// The vulnerabilities which affect a dependency.
+type VulnerabilityFinding struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// The IDs of the vulnerabilities, sorted.
+	Ids []string `json:"ids"`
+	// The lowest version which fixes all the vulnerabilities, or an empty string if one of them has no fix.
+	FixedVersion string            `json:"fixedVersion,omitempty"`
+	Entries      []*utils.OsvEntry `json:"-"`
+}
+
+// Matches the dependencies, which are in the format returned by cmd.GetDependenciesList, against the OSV database.
+// Returns a finding for each affected dependency, sorted by the module path.
+func GetVulnerabilities(dependencies map[string]bool, db *utils.OsvDatabase) []VulnerabilityFinding {
+	var findings []VulnerabilityFinding
+	for dependency := range dependencies {
+		modulePath, version := splitDependency(dependency)
+		if version == "" {
+			continue
+		}
+		entries := db.Match(modulePath, version)
+		if len(entries) == 0 {
+			continue
+		}
+		finding := VulnerabilityFinding{Path: modulePath, Version: version, Entries: entries}
+		hasFix := true
+		for _, entry := range entries {
+			finding.Ids = append(finding.Ids, entry.Id)
+			fixed := entry.FixedVersion(modulePath, version)
+			hasFix = hasFix && fixed != ""
+			if semver.Compare(fixed, finding.FixedVersion) > 0 {
+				finding.FixedVersion = fixed
+			}
+		}
+		if !hasFix {
+			finding.FixedVersion = ""
+		}
+		findings = append(findings, finding)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Path < findings[j].Path
+	})
+	return findings
+}
+
+// Adds a property to the build-info module for each finding, in the format 'go.vulnerabilities.<path>@<version>=<id>,<id>...'.
+// Existing properties of the module are kept. An error is returned if they can't be merged with the added properties.
+func AddVulnerabilitiesProperties(module *buildinfo.Module, findings []VulnerabilityFinding) error {
+	properties := map[string]string{}
+	for _, finding := range findings {
+		properties[VulnerabilitiesPropertyPrefix+finding.Path+"@"+finding.Version] = strings.Join(finding.Ids, ",")
+	}
+	return addModuleProperties(module, properties)
+}
@@ -0,0 +1,43 @@
+package executers
+
+import (
+	"path/filepath"
+	"testing"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVulnerabilities(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	db, err := utils.LoadOsvDatabase(filepath.Join("..", "testdata", "osv"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	dependencies := map[string]bool{
+		"github.com/jfrog/main@":                        true,
+		"github.com/jfrog/lib@v1.3.0":                   true,
+		"github.com/jfrog/docker@v20.10.0+incompatible": true,
+		"github.com/jfrog/other@v1.0.0":                 true,
+	}
+	findings := GetVulnerabilities(dependencies, db)
+	if !assert.Len(t, findings, 2) {
+		return
+	}
+	assert.Equal(t, "github.com/jfrog/docker", findings[0].Path)
+	assert.Equal(t, []string{"GO-2022-0002"}, findings[0].Ids)
+	// The vulnerability has no fix.
+	assert.Empty(t, findings[0].FixedVersion)
+	assert.Equal(t, "github.com/jfrog/lib", findings[1].Path)
+	assert.Equal(t, "v1.3.1", findings[1].FixedVersion)
+
+	module := &buildinfo.Module{Id: "github.com/jfrog/main", Properties: map[string]string{"key": "value"}}
+	assert.NoError(t, AddVulnerabilitiesProperties(module, findings))
+	assert.Equal(t, map[string]string{
+		"key": "value",
+		"go.vulnerabilities.github.com/jfrog/docker@v20.10.0+incompatible": "GO-2022-0002",
+		"go.vulnerabilities.github.com/jfrog/lib@v1.3.0":                   "GO-2022-0001",
+	}, module.Properties)
+}
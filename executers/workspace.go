@@ -0,0 +1,55 @@
+package executers
+
+import (
+	"strings"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/executers/utils"
+)
+
+// CreateWorkspaceBuildInfoModules returns a build-info module for each member of the workspace.
+// The dependencies of each module are the modules required by the member packages, which have a zip in the Go cache.
+// Other members of the workspace are not included as dependencies, since they are built from source.
+// The dependencies checks of the options, such as a NoticesCheck, are run on the dependencies of each member.
+func CreateWorkspaceBuildInfoModules(workspace *cmd.Workspace, cachePath string, options ...utils.Option) ([]buildinfo.Module, error) {
+	var modules []buildinfo.Module
+	for _, member := range workspace.Members {
+		dependenciesList, err := cmd.GetWorkspaceMemberDependenciesList(workspace, member, options...)
+		if err != nil {
+			return nil, err
+		}
+		for dependency := range dependenciesList {
+			if workspace.GetMember(strings.Split(dependency, "@")[0]) != nil {
+				delete(dependenciesList, dependency)
+			}
+		}
+		module, err := createBuildInfoModule(member.Path, member.Dir, dependenciesList, cachePath, options...)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, *module)
+	}
+	return modules, nil
+}
+
+// Creates a build-info module, which its dependencies are the zips of the dependencies found in the Go cache.
+// If the module at moduleDir is built in vendor mode, the vendored dependencies are included as well. See GetProjectDependencies.
+func createBuildInfoModule(moduleId, moduleDir string, dependenciesList map[string]bool, cachePath string, options ...utils.Option) (*buildinfo.Module, error) {
+	packages, err := GetProjectDependencies(moduleDir, cachePath, dependenciesList, options...)
+	if err != nil {
+		return nil, err
+	}
+	module := &buildinfo.Module{Id: moduleId, Type: buildinfo.Go}
+	for i := range packages {
+		// The checksums of vendored dependencies are calculated from their sources.
+		if packages[i].GetZipPath() != "" {
+			err = packages[i].PopulateZip()
+			if err != nil {
+				return nil, err
+			}
+		}
+		module.Dependencies = append(module.Dependencies, packages[i].Dependencies()...)
+	}
+	return module, nil
+}
@@ -0,0 +1,213 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	artifactoryauth "github.com/jfrog/jfrog-client-go/artifactory/auth"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/config"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// The path of the project config, relative to the project root.
+	ProjectConfigPath = ".jfrog/projects/go.yaml"
+	// The prefix of the env vars which provide the server details, such as JFROG_SERVER_MY_SERVER_URL for the server ID my-server.
+	ServerEnvPrefix = "JFROG_SERVER_"
+	goPackageType   = "go"
+)
+
+var serverIdEnvRegExp = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// The resolver and deployer config of a Go project, in the format of .jfrog/projects/go.yaml.
+type ProjectConfig struct {
+	Version  int         `yaml:"version"`
+	Type     string      `yaml:"type"`
+	Resolver *RepoConfig `yaml:"resolver,omitempty"`
+	Deployer *RepoConfig `yaml:"deployer,omitempty"`
+	// Additional deployers, such as a disaster recovery instance, which the modules are published to in addition to the deployer.
+	Deployers []RepoConfig `yaml:"deployers,omitempty"`
+}
+
+type RepoConfig struct {
+	Repo     string `yaml:"repo"`
+	ServerId string `yaml:"serverId"`
+}
+
+// The details of the servers, by their IDs.
+type CredentialsConfig struct {
+	Servers []ServerConfig `yaml:"servers"`
+}
+
+type ServerConfig struct {
+	ServerId    string `yaml:"serverId"`
+	Url         string `yaml:"url"`
+	User        string `yaml:"user,omitempty"`
+	Password    string `yaml:"password,omitempty"`
+	AccessToken string `yaml:"accessToken,omitempty"`
+}
+
+// Reads and validates a project config.
+func ReadProjectConfig(configPath string) (*ProjectConfig, error) {
+	content, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	projectConfig := &ProjectConfig{}
+	if err = yaml.Unmarshal(content, projectConfig); err != nil {
+		return nil, errorutils.CheckError(errors.New(fmt.Sprintf("Failed parsing %s: %s", configPath, err.Error())))
+	}
+	if projectConfig.Type != "" && projectConfig.Type != goPackageType {
+		return nil, errorutils.CheckError(errors.New(fmt.Sprintf("%s is a config of a %s project, rather than a Go project", configPath, projectConfig.Type)))
+	}
+	for _, repoConfig := range projectConfig.getRepoConfigs() {
+		if repoConfig.Repo == "" || repoConfig.ServerId == "" {
+			return nil, errorutils.CheckError(errors.New(fmt.Sprintf("Both repo and serverId are required for each resolver and deployer in %s", configPath)))
+		}
+	}
+	return projectConfig, nil
+}
+
+func (projectConfig *ProjectConfig) getRepoConfigs() []RepoConfig {
+	var repoConfigs []RepoConfig
+	if projectConfig.Resolver != nil {
+		repoConfigs = append(repoConfigs, *projectConfig.Resolver)
+	}
+	if projectConfig.Deployer != nil {
+		repoConfigs = append(repoConfigs, *projectConfig.Deployer)
+	}
+	return append(repoConfigs, projectConfig.Deployers...)
+}
+
+// Reads a credentials file, which may also be in JSON format. Returns the server details by the server ID.
+// Each server ID may appear only once in the file.
+func ReadCredentials(credentialsPath string) (map[string]ServerConfig, error) {
+	content, err := ioutil.ReadFile(credentialsPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	credentials := &CredentialsConfig{}
+	if err = yaml.Unmarshal(content, credentials); err != nil {
+		return nil, errorutils.CheckError(errors.New(fmt.Sprintf("Failed parsing %s: %s", credentialsPath, err.Error())))
+	}
+	servers := map[string]ServerConfig{}
+	for _, server := range credentials.Servers {
+		if _, exists := servers[server.ServerId]; exists {
+			return nil, errorutils.CheckError(errors.New(fmt.Sprintf("Server %s appears more than once in %s", server.ServerId, credentialsPath)))
+		}
+		servers[server.ServerId] = server
+	}
+	return servers, nil
+}
+
+// Returns the details of the server, from the credentials and from the env vars.
+// The env vars are named by the server ID in upper case, with the non-alphanumeric characters replaced by underscores:
+// JFROG_SERVER_<ID>_URL, JFROG_SERVER_<ID>_USER, JFROG_SERVER_<ID>_PASSWORD and JFROG_SERVER_<ID>_ACCESS_TOKEN.
+// The env vars take precedence over the credentials, so that secrets can be provided by CI.
+func GetServerConfig(serverId string, credentials map[string]ServerConfig) (ServerConfig, error) {
+	server, ok := credentials[serverId]
+	if !ok {
+		server = ServerConfig{ServerId: serverId}
+	}
+	envPrefix := ServerEnvPrefix + strings.Trim(serverIdEnvRegExp.ReplaceAllString(strings.ToUpper(serverId), "_"), "_") + "_"
+	for suffix, field := range map[string]*string{"URL": &server.Url, "USER": &server.User, "PASSWORD": &server.Password, "ACCESS_TOKEN": &server.AccessToken} {
+		if value, ok := os.LookupEnv(envPrefix + suffix); ok {
+			*field = value
+		}
+	}
+	if server.Url == "" {
+		return server, errorutils.CheckError(errors.New(fmt.Sprintf("The URL of server %s is missing. Add it to the credentials file, or set %sURL", serverId, envPrefix)))
+	}
+	return server, nil
+}
+
+func (server ServerConfig) createServiceManager() (artifactory.ArtifactoryServicesManager, error) {
+	details := artifactoryauth.NewArtifactoryDetails()
+	details.SetUrl(utils.AddTrailingSlashIfNeeded(server.Url))
+	details.SetUser(server.User)
+	details.SetPassword(server.Password)
+	details.SetAccessToken(server.AccessToken)
+	serviceConfig, err := config.NewConfigBuilder().SetServiceDetails(details).Build()
+	if err != nil {
+		return nil, err
+	}
+	return artifactory.New(serviceConfig)
+}
+
+// Loads the resolver and deployers of a project config into a ResolverDeployer.
+// The server details are read from the credentials file, if credentialsPath isn't empty, and from the env vars. See GetServerConfig.
+// Each configured repository is verified to exist on its server and to be a Go repository.
+func LoadResolverDeployer(configPath, credentialsPath string) (*ResolverDeployer, error) {
+	projectConfig, err := ReadProjectConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	credentials := map[string]ServerConfig{}
+	if credentialsPath != "" {
+		if credentials, err = ReadCredentials(credentialsPath); err != nil {
+			return nil, err
+		}
+	}
+	serviceManagers := map[string]artifactory.ArtifactoryServicesManager{}
+	loadParams := func(repoConfig RepoConfig) (*Params, error) {
+		serviceManager, ok := serviceManagers[repoConfig.ServerId]
+		if !ok {
+			server, err := GetServerConfig(repoConfig.ServerId, credentials)
+			if err != nil {
+				return nil, err
+			}
+			if serviceManager, err = server.createServiceManager(); err != nil {
+				return nil, err
+			}
+			serviceManagers[repoConfig.ServerId] = serviceManager
+		}
+		if err := validateGoRepo(repoConfig, serviceManager); err != nil {
+			return nil, err
+		}
+		return (&Params{}).SetRepo(repoConfig.Repo).SetServiceManager(serviceManager), nil
+	}
+
+	resolverDeployer := &ResolverDeployer{}
+	if projectConfig.Resolver != nil {
+		resolver, err := loadParams(*projectConfig.Resolver)
+		if err != nil {
+			return nil, err
+		}
+		resolverDeployer.SetResolver(resolver)
+	}
+	if projectConfig.Deployer != nil {
+		deployer, err := loadParams(*projectConfig.Deployer)
+		if err != nil {
+			return nil, err
+		}
+		resolverDeployer.SetDeployer(deployer)
+	}
+	for _, repoConfig := range projectConfig.Deployers {
+		deployer, err := loadParams(repoConfig)
+		if err != nil {
+			return nil, err
+		}
+		resolverDeployer.AddDeployer(deployer)
+	}
+	return resolverDeployer, nil
+}
+
+// Returns an error if the repository doesn't exist on the server, or isn't a Go repository.
+func validateGoRepo(repoConfig RepoConfig, serviceManager artifactory.ArtifactoryServicesManager) error {
+	repoDetails := &services.RepositoryDetails{}
+	if err := serviceManager.GetRepository(repoConfig.Repo, repoDetails); err != nil {
+		return errorutils.CheckError(errors.New(fmt.Sprintf("Failed getting repository %s from server %s: %s", repoConfig.Repo, repoConfig.ServerId, err.Error())))
+	}
+	if !strings.EqualFold(repoDetails.PackageType, goPackageType) {
+		return errorutils.CheckError(errors.New(fmt.Sprintf("Repository %s on server %s is not a Go repository. Its package type is %s", repoConfig.Repo, repoConfig.ServerId, repoDetails.PackageType)))
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package params
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/gocmd/tests/artifactorytest"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadResolverDeployer(t *testing.T) {
+	log.SetLogger(log.NewLogger(log.ERROR, nil))
+	server, err := artifactorytest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	assert.NoError(t, server.AddRepo("go-virtual"))
+	assert.NoError(t, server.AddRepo("go-local"))
+	assert.NoError(t, server.AddRepo("go-dr"))
+	assert.NoError(t, server.AddRepoOfType("npm-local", "npm"))
+	tempDir, err := ioutil.TempDir("", "projectConfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configPath := writeTestFile(t, tempDir, "go.yaml", `version: 1
+type: go
+resolver:
+  repo: go-virtual
+  serverId: main
+deployer:
+  repo: go-local
+  serverId: main
+deployers:
+  - repo: go-dr
+    serverId: dr-server
+`)
+	credentialsPath := writeTestFile(t, tempDir, "credentials.yaml", `servers:
+  - serverId: main
+    url: `+server.URL+`
+    user: `+artifactorytest.DefaultUser+`
+    password: `+artifactorytest.DefaultPassword+`
+`)
+	// The details of dr-server are provided by the env vars only.
+	for key, value := range map[string]string{"JFROG_SERVER_DR_SERVER_URL": server.URL, "JFROG_SERVER_DR_SERVER_USER": artifactorytest.DefaultUser, "JFROG_SERVER_DR_SERVER_PASSWORD": artifactorytest.DefaultPassword} {
+		assert.NoError(t, os.Setenv(key, value))
+		defer os.Unsetenv(key)
+	}
+	resolverDeployer, err := LoadResolverDeployer(configPath, credentialsPath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "go-virtual", resolverDeployer.Resolver().Repo())
+		assert.Equal(t, "go-local", resolverDeployer.Deployer().Repo())
+		if assert.Len(t, resolverDeployer.Deployers(), 2) {
+			assert.Equal(t, "go-dr", resolverDeployer.Deployers()[1].Repo())
+		}
+		assert.Equal(t, server.URL+"/", resolverDeployer.Resolver().ServiceManager().GetConfig().GetServiceDetails().GetUrl())
+	}
+
+	// The env vars take precedence over the credentials file.
+	assert.NoError(t, os.Setenv("JFROG_SERVER_MAIN_PASSWORD", "wrong"))
+	defer os.Unsetenv("JFROG_SERVER_MAIN_PASSWORD")
+	_, err = LoadResolverDeployer(configPath, credentialsPath)
+	assert.Error(t, err)
+	assert.NoError(t, os.Unsetenv("JFROG_SERVER_MAIN_PASSWORD"))
+
+	// The details of a server are required.
+	_, err = LoadResolverDeployer(configPath, "")
+	assert.EqualError(t, err, "The URL of server main is missing. Add it to the credentials file, or set JFROG_SERVER_MAIN_URL")
+
+	// The repositories must exist, and be Go repositories.
+	configPath = writeTestFile(t, tempDir, "missing.yaml", "resolver:\n  repo: go-missing\n  serverId: main\n")
+	_, err = LoadResolverDeployer(configPath, credentialsPath)
+	assert.Error(t, err)
+	configPath = writeTestFile(t, tempDir, "npm.yaml", "deployer:\n  repo: npm-local\n  serverId: main\n")
+	_, err = LoadResolverDeployer(configPath, credentialsPath)
+	assert.EqualError(t, err, "Repository npm-local on server main is not a Go repository. Its package type is npm")
+}
+
+func TestReadProjectConfig(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "projectConfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	_, err = ReadProjectConfig(writeTestFile(t, tempDir, "npm.yaml", "type: npm\n"))
+	assert.Error(t, err)
+	_, err = ReadProjectConfig(writeTestFile(t, tempDir, "noServer.yaml", "resolver:\n  repo: go-virtual\n"))
+	assert.Error(t, err)
+	_, err = ReadProjectConfig(writeTestFile(t, tempDir, "malformed.yaml", "resolver: [\n"))
+	assert.Error(t, err)
+	projectConfig, err := ReadProjectConfig(writeTestFile(t, tempDir, "empty.yaml", "version: 1\ntype: go\n"))
+	if assert.NoError(t, err) {
+		assert.Nil(t, projectConfig.Resolver)
+	}
+}
+
+func TestReadCredentialsJson(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	servers, err := ReadCredentials(writeTestFile(t, tempDir, "credentials.json", `{"servers":[{"serverId":"main","url":"https://example.jfrog.io/artifactory","accessToken":"token"}]}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, ServerConfig{ServerId: "main", Url: "https://example.jfrog.io/artifactory", AccessToken: "token"}, servers["main"])
+	}
+}
+
+func TestReadCredentialsDuplicateServerId(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	credentials := "servers:\n" +
+		"  - serverId: main\n    url: https://example.jfrog.io/artifactory\n" +
+		"  - serverId: main\n    url: https://other.jfrog.io/artifactory\n"
+	_, err = ReadCredentials(writeTestFile(t, tempDir, "credentials.yaml", credentials))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Server main appears more than once")
+	}
+}
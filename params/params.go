@@ -7,7 +7,8 @@ import (
 
 type ResolverDeployer struct {
 	resolver *Params
-	deployer *Params
+	// The first deployer is the primary deployer.
+	deployers []*Params
 }
 
 func (resolverDeployer *ResolverDeployer) Resolver() *Params {
@@ -19,12 +20,28 @@ func (resolverDeployer *ResolverDeployer) SetResolver(resolver *Params) *Resolve
 	return resolverDeployer
 }
 
+// Returns the primary deployer, or nil if there are no deployers.
 func (resolverDeployer *ResolverDeployer) Deployer() *Params {
-	return resolverDeployer.deployer
+	if len(resolverDeployer.deployers) == 0 {
+		return nil
+	}
+	return resolverDeployer.deployers[0]
 }
 
+// Sets the primary deployer, and removes the additional deployers.
 func (resolverDeployer *ResolverDeployer) SetDeployer(deployer *Params) *ResolverDeployer {
-	resolverDeployer.deployer = deployer
+	resolverDeployer.deployers = []*Params{deployer}
+	return resolverDeployer
+}
+
+// Returns all the deployers, starting with the primary deployer.
+func (resolverDeployer *ResolverDeployer) Deployers() []*Params {
+	return resolverDeployer.deployers
+}
+
+// Adds a deployer, such as a disaster recovery instance, which the modules are published to in addition to the primary deployer.
+func (resolverDeployer *ResolverDeployer) AddDeployer(deployer *Params) *ResolverDeployer {
+	resolverDeployer.deployers = append(resolverDeployer.deployers, deployer)
 	return resolverDeployer
 }
 
@@ -0,0 +1,292 @@
+// Package artifactorytest provides an in-process stand-in for the Go API of Artifactory, for use in tests.
+package artifactorytest
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	artifactoryauth "github.com/jfrog/jfrog-client-go/artifactory/auth"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/config"
+	"golang.org/x/mod/module"
+)
+
+const (
+	DefaultUser     = "admin"
+	DefaultPassword = "password"
+	DefaultVersion  = "7.27.10"
+	goApiPrefix     = "/api/go/"
+	reposApiPrefix  = "/api/repositories/"
+)
+
+// Server emulates the Go API of Artifactory.
+// Each repository is stored in a temp directory using the Go module cache layout, and served using utils.ProxyHandler.
+// Uploads through the Go publish API are stored in the repository and recorded.
+type Server struct {
+	*httptest.Server
+	// The version returned by the api/system/version endpoint.
+	Version     string
+	user        string
+	password    string
+	accessToken string
+	reposDir    string
+	// The package types of the repositories, by the repository key.
+	repos    map[string]string
+	uploads  []Upload
+	requests []string
+	mutex    sync.Mutex
+}
+
+// A file uploaded through the Go publish API.
+type Upload struct {
+	Repo string
+	// The module path and version, as they appear in the URL.
+	Module  string
+	Version string
+	// The file extension: .zip, .mod or .info
+	Ext        string
+	Properties map[string]string
+	Content    []byte
+}
+
+// Starts a server which requires the default user and password.
+func NewServer() (*Server, error) {
+	reposDir, err := ioutil.TempDir("", "artifactorytest")
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{Version: DefaultVersion, user: DefaultUser, password: DefaultPassword, reposDir: reposDir, repos: map[string]string{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server, nil
+}
+
+// Stops the server and removes the stored repositories.
+func (server *Server) Close() {
+	server.Server.Close()
+	os.RemoveAll(server.reposDir)
+}
+
+// Sets the credentials required by the server. If both are empty, anonymous access is allowed.
+func (server *Server) SetCredentials(user, password string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.user, server.password = user, password
+}
+
+// Sets an access token accepted by the server, in addition to the user and password.
+func (server *Server) SetAccessToken(accessToken string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.accessToken = accessToken
+}
+
+// Creates an empty Go repository.
+func (server *Server) AddRepo(repo string) error {
+	return server.AddRepoOfType(repo, "go")
+}
+
+// Creates an empty repository of the provided package type. Only Go repositories are served by the Go API.
+func (server *Server) AddRepoOfType(repo, packageType string) error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.repos[repo] = packageType
+	return os.MkdirAll(server.RepoDir(repo), 0755)
+}
+
+// Returns the directory where the files of the repository are stored.
+func (server *Server) RepoDir(repo string) string {
+	return filepath.Join(server.reposDir, repo)
+}
+
+// Adds a module version to the repository. The keys of files are the extensions: .zip, .mod and .info
+func (server *Server) AddModule(repo, modulePath, version string, files map[string][]byte) error {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return err
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return err
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if server.repos[repo] == "" {
+		return fmt.Errorf("repository %s does not exist", repo)
+	}
+	for ext, content := range files {
+		if err = server.writeFile(repo, escapedPath, escapedVersion+ext, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the files uploaded to the server, in the order of their upload.
+func (server *Server) Uploads() []Upload {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]Upload{}, server.uploads...)
+}
+
+// Returns the requests received by the server, in the format '<method> <path>'.
+func (server *Server) Requests() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]string{}, server.requests...)
+}
+
+// Returns details for accessing the server with the configured user and password.
+func (server *Server) ServiceDetails() auth.ServiceDetails {
+	details := artifactoryauth.NewArtifactoryDetails()
+	details.SetUrl(server.URL + "/")
+	details.SetUser(server.user)
+	details.SetPassword(server.password)
+	return details
+}
+
+// Returns a services manager for accessing the server with the configured user and password.
+func (server *Server) ServicesManager() (artifactory.ArtifactoryServicesManager, error) {
+	serviceConfig, err := config.NewConfigBuilder().SetServiceDetails(server.ServiceDetails()).Build()
+	if err != nil {
+		return nil, err
+	}
+	return artifactory.New(serviceConfig)
+}
+
+func (server *Server) handle(w http.ResponseWriter, r *http.Request) {
+	server.mutex.Lock()
+	server.requests = append(server.requests, r.Method+" "+r.URL.EscapedPath())
+	authorized := server.isAuthorized(r)
+	server.mutex.Unlock()
+	if !authorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Artifactory Realm"`)
+		writeErrors(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+	switch {
+	case r.URL.Path == "/api/system/version" && r.Method == http.MethodGet:
+		writeJson(w, map[string]string{"version": server.Version})
+	case strings.HasPrefix(r.URL.Path, reposApiPrefix) && r.Method == http.MethodGet:
+		server.handleGetRepo(w, r)
+	case strings.HasPrefix(r.URL.Path, goApiPrefix):
+		server.handleGoApi(w, r)
+	default:
+		writeErrors(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (server *Server) isAuthorized(r *http.Request) bool {
+	if server.user == "" && server.password == "" {
+		return true
+	}
+	if user, password, ok := r.BasicAuth(); ok {
+		return user == server.user && password == server.password
+	}
+	return server.accessToken != "" && r.Header.Get("Authorization") == "Bearer "+server.accessToken
+}
+
+// Handles a repository configuration request: GET api/repositories/<repo>
+func (server *Server) handleGetRepo(w http.ResponseWriter, r *http.Request) {
+	repo := strings.TrimPrefix(r.URL.Path, reposApiPrefix)
+	server.mutex.Lock()
+	packageType := server.repos[repo]
+	server.mutex.Unlock()
+	if packageType == "" {
+		writeErrors(w, http.StatusBadRequest, fmt.Sprintf("Repository %s does not exist", repo))
+		return
+	}
+	writeJson(w, map[string]string{"key": repo, "rclass": "local", "packageType": packageType})
+}
+
+func (server *Server) handleGoApi(w http.ResponseWriter, r *http.Request) {
+	repoPath := strings.TrimPrefix(r.URL.Path, goApiPrefix)
+	repo := strings.SplitN(repoPath, "/", 2)[0]
+	server.mutex.Lock()
+	repoExists := server.repos[repo] == "go"
+	server.mutex.Unlock()
+	if !repoExists {
+		writeErrors(w, http.StatusNotFound, fmt.Sprintf("Repository %s not found", repo))
+		return
+	}
+	if r.Method == http.MethodPut {
+		server.handleUpload(w, r, repo, strings.TrimPrefix(repoPath, repo))
+		return
+	}
+	http.StripPrefix(goApiPrefix+repo, utils.NewProxyHandler(server.RepoDir(repo))).ServeHTTP(w, r)
+}
+
+// Handles a Go publish request: PUT api/go/<repo>/<module>/@v/<version><ext>;<key>=<value>;...
+func (server *Server) handleUpload(w http.ResponseWriter, r *http.Request, repo, filePath string) {
+	parts := strings.Split(filePath, ";")
+	index := strings.LastIndex(parts[0], "/@v/")
+	if index <= 0 {
+		writeErrors(w, http.StatusBadRequest, "Unexpected Go publish path: "+filePath)
+		return
+	}
+	fileName := parts[0][index+len("/@v/"):]
+	ext := filepath.Ext(fileName)
+	upload := Upload{Repo: repo, Module: strings.TrimPrefix(parts[0][:index], "/"), Version: strings.TrimSuffix(fileName, ext), Ext: ext, Properties: map[string]string{}}
+	for _, property := range parts[1:] {
+		keyValue := strings.SplitN(property, "=", 2)
+		if len(keyValue) == 2 {
+			upload.Properties[keyValue[0]] = keyValue[1]
+		}
+	}
+	content, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	upload.Content = content
+	sha1Sum := sha1.Sum(content)
+	if expected := r.Header.Get("X-Checksum-Sha1"); expected != "" && expected != hex.EncodeToString(sha1Sum[:]) {
+		writeErrors(w, http.StatusConflict, "Checksum mismatch for "+filePath)
+		return
+	}
+
+	server.mutex.Lock()
+	err = server.writeFile(repo, upload.Module, fileName, content)
+	if err == nil {
+		server.uploads = append(server.uploads, upload)
+	}
+	server.mutex.Unlock()
+	if err != nil {
+		writeErrors(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sha256Sum := sha256.Sum256(content)
+	w.Header().Set("X-Checksum-Sha256", hex.EncodeToString(sha256Sum[:]))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (server *Server) writeFile(repo, escapedPath, fileName string, content []byte) error {
+	dir := filepath.Join(server.RepoDir(repo), filepath.FromSlash(escapedPath), "@v")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, fileName), content, 0644)
+}
+
+func writeJson(w http.ResponseWriter, content interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(content)
+}
+
+// Writes an error in the format used by Artifactory.
+func writeErrors(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": []map[string]interface{}{{"status": status, "message": message}}})
+}